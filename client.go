@@ -7,6 +7,7 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -119,6 +120,53 @@ func (c *Client) doRequest(ctx context.Context, method, path string, body interf
 	return c.httpClient.Do(req)
 }
 
+// APIError is returned when the GitHub API responds with a non-success status
+type APIError struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s %s failed with status %d: %s", e.Method, e.Path, e.StatusCode, e.Body)
+}
+
+func newAPIError(method, path string, resp *http.Response) *APIError {
+	body, _ := io.ReadAll(resp.Body)
+	return &APIError{
+		Method:     method,
+		Path:       path,
+		StatusCode: resp.StatusCode,
+		Body:       string(body),
+	}
+}
+
+// isNotFound reports whether err is a GitHub API 404 response
+func isNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// isConflict reports whether err is a GitHub API 409 response
+func isConflict(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusConflict
+}
+
+// isTransient reports whether err may go away on retry: a network failure, a timeout, rate
+// limiting or a GitHub server error. The request may still have taken effect on GitHub.
+func isTransient(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return true
+}
+
 func (c *Client) Get(ctx context.Context, path string, result interface{}) error {
 	resp, err := c.doRequest(ctx, "GET", path, nil)
 	if err != nil {
@@ -127,13 +175,41 @@ func (c *Client) Get(ctx context.Context, path string, result interface{}) error
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("GET %s failed with status %d: %s", path, resp.StatusCode, string(body))
+		return newAPIError("GET", path, resp)
 	}
 
 	return json.NewDecoder(resp.Body).Decode(result)
 }
 
+// listPageSize is the number of items requested per page from list endpoints
+const listPageSize = 100
+
+// getAllPages walks a paginated list endpoint and returns the items from every page.
+// items extracts the reported total count and the items from one decoded page; a
+// negative total means the endpoint does not report one, so paging stops on the
+// first short page.
+func getAllPages[L any, T any](ctx context.Context, c *Client, path string, items func(*L) (int, []T)) ([]T, error) {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+
+	var all []T
+	for page := 1; ; page++ {
+		var list L
+		err := c.Get(ctx, fmt.Sprintf("%s%sper_page=%d&page=%d", path, separator, listPageSize, page), &list)
+		if err != nil {
+			return nil, err
+		}
+
+		total, pageItems := items(&list)
+		all = append(all, pageItems...)
+		if len(pageItems) < listPageSize || (total >= 0 && len(all) >= total) {
+			return all, nil
+		}
+	}
+}
+
 func (c *Client) Post(ctx context.Context, path string, body, result interface{}) error {
 	resp, err := c.doRequest(ctx, "POST", path, body)
 	if err != nil {
@@ -142,8 +218,7 @@ func (c *Client) Post(ctx context.Context, path string, body, result interface{}
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("POST %s failed with status %d: %s", path, resp.StatusCode, string(body))
+		return newAPIError("POST", path, resp)
 	}
 
 	if result != nil {
@@ -160,8 +235,7 @@ func (c *Client) Put(ctx context.Context, path string, body, result interface{})
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("PUT %s failed with status %d: %s", path, resp.StatusCode, string(body))
+		return newAPIError("PUT", path, resp)
 	}
 
 	if result != nil {
@@ -178,8 +252,7 @@ func (c *Client) Patch(ctx context.Context, path string, body, result interface{
 	defer resp.Body.Close()
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("PATCH %s failed with status %d: %s", path, resp.StatusCode, string(body))
+		return newAPIError("PATCH", path, resp)
 	}
 
 	if result != nil {
@@ -223,8 +296,7 @@ func (c *Client) Delete(ctx context.Context, path string, body interface{}) erro
 		if resp.StatusCode == http.StatusNotFound {
 			return nil
 		}
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("DELETE %s failed with status %d: %s", path, resp.StatusCode, string(body))
+		return newAPIError("DELETE", path, resp)
 	}
 
 	return nil
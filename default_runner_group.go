@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceDefaultRunnerGroup() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Adopts and manages the settings of the organization's built-in Default runner group. The group is never created or deleted; on destroy its original settings are restored.",
+		CreateContext: resourceDefaultRunnerGroupCreate,
+		ReadContext:   resourceDefaultRunnerGroupRead,
+		UpdateContext: resourceDefaultRunnerGroupUpdate,
+		DeleteContext: resourceDefaultRunnerGroupDelete,
+		CustomizeDiff: resourceRunnerGroupCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceDefaultRunnerGroupImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.StringInSlice([]string{"all", "selected", "private"}, false),
+				Description:  "Visibility of the runner group",
+			},
+			"selected_repository_ids": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeInt},
+				Description: "List of repository IDs that can access the runner group",
+			},
+			"allows_public_repositories": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether public repositories can use the runner group",
+			},
+			"restricted_to_workflows": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Computed:    true,
+				Description: "Whether the runner group is restricted to specific workflows",
+			},
+			"selected_workflows": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"selected_workflow"},
+				Description:   "List of workflows that can use the runner group",
+			},
+			"selected_workflow": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"selected_workflows"},
+				Description:   "Structured workflow references that can use the runner group. Each block is rendered into `selected_workflows` and checked against the repository contents at plan time",
+				Elem:          selectedWorkflowResource(),
+			},
+			"network_configuration_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The identifier of a hosted compute network configuration",
+			},
+			"restore_on_destroy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to restore the settings captured at adoption time when the resource is destroyed",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the runner group",
+			},
+			"inherited": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the runner group is inherited",
+			},
+			"selected_repositories_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL for selected repositories",
+			},
+			"runners_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL for runners",
+			},
+			"hosted_runners_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL for hosted runners",
+			},
+			"workflow_restrictions_read_only": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether workflow restrictions are read-only",
+			},
+			"original_settings": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Settings of the Default runner group captured when it was adopted",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"visibility": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"selected_repository_ids": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"allows_public_repositories": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"restricted_to_workflows": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"selected_workflows": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"network_configuration_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceDefaultRunnerGroupCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	// The Default group always exists, so look it up instead of creating it
+	runnerGroups, err := listRunnerGroups(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var defaultGroup *RunnerGroup
+	for _, rg := range runnerGroups {
+		if rg.Default {
+			defaultGroup = &rg
+			break
+		}
+	}
+
+	if defaultGroup == nil {
+		return diag.Errorf("Default runner group not found in organization '%s'", client.organization)
+	}
+
+	runnerGroupID := strconv.Itoa(defaultGroup.ID)
+
+	// Capture the current settings so they can be restored on destroy
+	original, err := captureDefaultRunnerGroupSettings(ctx, client, defaultGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(runnerGroupID)
+	d.Set("original_settings", []interface{}{original})
+
+	// Settings left out of the configuration keep their current values
+	config := d.GetRawConfig()
+	for _, key := range []string{"visibility", "allows_public_repositories", "restricted_to_workflows", "selected_workflows", "network_configuration_id"} {
+		if key == "selected_workflows" && len(d.Get("selected_workflow").([]interface{})) > 0 {
+			continue
+		}
+		if config.GetAttr(key).IsNull() {
+			d.Set(key, original[key])
+		}
+	}
+
+	return resourceDefaultRunnerGroupUpdate(ctx, d, m)
+}
+
+// resourceDefaultRunnerGroupImport captures the group's current settings as original_settings,
+// so restore_on_destroy has something to restore for imported groups too
+func resourceDefaultRunnerGroupImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	var runnerGroup RunnerGroup
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s", client.organization, d.Id()), &runnerGroup)
+	if err != nil {
+		return nil, err
+	}
+	if !runnerGroup.Default {
+		return nil, fmt.Errorf("runner group %s is not the Default runner group", d.Id())
+	}
+
+	original, err := captureDefaultRunnerGroupSettings(ctx, client, &runnerGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("original_settings", []interface{}{original})
+	d.Set("restore_on_destroy", true)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+// captureDefaultRunnerGroupSettings returns the settings of the Default runner group in the
+// shape of original_settings
+func captureDefaultRunnerGroupSettings(ctx context.Context, client *Client, runnerGroup *RunnerGroup) (map[string]interface{}, error) {
+	original := map[string]interface{}{
+		"visibility":                 runnerGroup.Visibility,
+		"selected_repository_ids":    []int{},
+		"allows_public_repositories": runnerGroup.AllowsPublicRepositories,
+		"restricted_to_workflows":    runnerGroup.RestrictedToWorkflows,
+		"selected_workflows":         runnerGroup.SelectedWorkflows,
+		"network_configuration_id":   runnerGroup.NetworkConfigurationID,
+	}
+	if runnerGroup.Visibility == "selected" {
+		repositories, err := listRunnerGroupRepositories(ctx, client, strconv.Itoa(runnerGroup.ID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get runner group repositories: %v", err)
+		}
+		repositoryIDs := make([]int, len(repositories))
+		for i, repo := range repositories {
+			repositoryIDs[i] = repo.ID
+		}
+		original["selected_repository_ids"] = repositoryIDs
+	}
+	return original, nil
+}
+
+func resourceDefaultRunnerGroupRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerGroupID := d.Id()
+	var runnerGroup RunnerGroup
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s", client.organization, runnerGroupID), &runnerGroup)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if !runnerGroup.Default {
+		return diag.Errorf("runner group %s is not the Default runner group", runnerGroupID)
+	}
+
+	d.Set("name", runnerGroup.Name)
+	d.Set("visibility", runnerGroup.Visibility)
+	d.Set("allows_public_repositories", runnerGroup.AllowsPublicRepositories)
+	d.Set("restricted_to_workflows", runnerGroup.RestrictedToWorkflows)
+	d.Set("selected_workflows", runnerGroup.SelectedWorkflows)
+	d.Set("network_configuration_id", runnerGroup.NetworkConfigurationID)
+	d.Set("inherited", runnerGroup.Inherited)
+	d.Set("selected_repositories_url", runnerGroup.SelectedRepositoriesURL)
+	d.Set("runners_url", runnerGroup.RunnersURL)
+	d.Set("hosted_runners_url", runnerGroup.HostedRunnersURL)
+	d.Set("workflow_restrictions_read_only", runnerGroup.WorkflowRestrictionsReadOnly)
+
+	if len(d.Get("selected_workflow").([]interface{})) > 0 {
+		d.Set("selected_workflow", flattenSelectedWorkflows(runnerGroup.SelectedWorkflows))
+	}
+
+	return nil
+}
+
+func resourceDefaultRunnerGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerGroupID := d.Id()
+	visibility := d.Get("visibility").(string)
+	selectedRepositoryIDs := expandIntList(d.Get("selected_repository_ids").([]interface{}))
+
+	// Validate visibility and selected_repository_ids, unless the repository
+	// list is left unmanaged and keeps whatever the group already has
+	if !d.GetRawConfig().GetAttr("selected_repository_ids").IsNull() {
+		if err := validateRunnerGroupRepositories(visibility, selectedRepositoryIDs); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	networkConfigID := d.Get("network_configuration_id").(string)
+	req := &UpdateRunnerGroupRequest{
+		Visibility:               visibility,
+		AllowsPublicRepositories: boolPtr(d.Get("allows_public_repositories").(bool)),
+		RestrictedToWorkflows:    boolPtr(d.Get("restricted_to_workflows").(bool)),
+		SelectedWorkflows:        expandStringList(d.Get("selected_workflows").([]interface{})),
+		NetworkConfigurationID:   &networkConfigID,
+	}
+
+	err := updateRunnerGroup(ctx, client, runnerGroupID, req)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Update repositories if changed
+	if d.HasChange("selected_repository_ids") {
+		err := setRunnerGroupRepositories(ctx, client, runnerGroupID, selectedRepositoryIDs)
+		if err != nil {
+			return diag.Errorf("failed to update runner group repositories: %v", err)
+		}
+	}
+
+	return resourceDefaultRunnerGroupRead(ctx, d, m)
+}
+
+func resourceDefaultRunnerGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerGroupID := d.Id()
+
+	// The Default group cannot be deleted, so only put back what was there before
+	originalSettings := d.Get("original_settings").([]interface{})
+	if d.Get("restore_on_destroy").(bool) && len(originalSettings) == 0 {
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Default runner group settings were not restored",
+			Detail:   fmt.Sprintf("No original settings were captured for runner group %s, so its current settings were left in place.", runnerGroupID),
+		}}
+	}
+	if d.Get("restore_on_destroy").(bool) {
+		original := originalSettings[0].(map[string]interface{})
+
+		networkConfigID := original["network_configuration_id"].(string)
+		req := &UpdateRunnerGroupRequest{
+			Visibility:               original["visibility"].(string),
+			AllowsPublicRepositories: boolPtr(original["allows_public_repositories"].(bool)),
+			RestrictedToWorkflows:    boolPtr(original["restricted_to_workflows"].(bool)),
+			SelectedWorkflows:        expandStringList(original["selected_workflows"].([]interface{})),
+			NetworkConfigurationID:   &networkConfigID,
+		}
+
+		err := updateRunnerGroup(ctx, client, runnerGroupID, req)
+		if err != nil {
+			return diag.Errorf("failed to restore Default runner group settings: %v", err)
+		}
+
+		if req.Visibility == "selected" {
+			repositoryIDs := expandIntList(original["selected_repository_ids"].([]interface{}))
+			err := setRunnerGroupRepositories(ctx, client, runnerGroupID, repositoryIDs)
+			if err != nil {
+				return diag.Errorf("failed to restore Default runner group repositories: %v", err)
+			}
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,4 @@
+# Retrieve a GitHub-hosted larger runner by name
+data "azure-github-runners_hosted_runner" "example" {
+  name = "linux-4-core"
+}
@@ -0,0 +1,8 @@
+# Retrieve a custom image and its versions by name
+data "azure-github-runners_hosted_runner_custom_image" "toolchain" {
+  name = "build-toolchain"
+}
+
+output "latest_version" {
+  value = data.azure-github-runners_hosted_runner_custom_image.toolchain.latest_version
+}
@@ -0,0 +1,4 @@
+# List the organization's Linux x64 custom images
+data "azure-github-runners_hosted_runner_custom_images" "linux" {
+  platform = "linux-x64"
+}
@@ -0,0 +1,10 @@
+# List the GitHub-owned Linux x64 images
+data "azure-github-runners_hosted_runner_images" "linux" {
+  source   = "github-owned"
+  platform = "linux-x64"
+}
+
+# List partner images
+data "azure-github-runners_hosted_runner_images" "partner" {
+  source = "partner"
+}
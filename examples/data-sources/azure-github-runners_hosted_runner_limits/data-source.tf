@@ -0,0 +1,21 @@
+# The API does not report the hosted runner concurrency quota, so pass the limit
+# of the organization's plan and fail the check when runners can scale past it
+variable "maximum_runners_quota" {
+  type    = number
+  default = 100
+}
+
+data "azure-github-runners_hosted_runner_limits" "current" {
+  maximum_runners_quota = var.maximum_runners_quota
+}
+
+check "hosted_runner_quota" {
+  assert {
+    condition     = !data.azure-github-runners_hosted_runner_limits.current.exceeds_quota
+    error_message = "Hosted runners may scale above the organization quota."
+  }
+}
+
+output "public_ips_available" {
+  value = data.azure-github-runners_hosted_runner_limits.current.public_ips_maximum - data.azure-github-runners_hosted_runner_limits.current.public_ips_current_usage
+}
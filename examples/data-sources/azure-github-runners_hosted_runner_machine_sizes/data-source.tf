@@ -0,0 +1,5 @@
+# Find machine sizes with at least 8 cores and 32 GB of memory
+data "azure-github-runners_hosted_runner_machine_sizes" "large" {
+  min_cpu_cores = 8
+  min_memory_gb = 32
+}
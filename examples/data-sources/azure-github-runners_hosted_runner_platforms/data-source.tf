@@ -0,0 +1,2 @@
+# List the platforms available to GitHub-hosted runners
+data "azure-github-runners_hosted_runner_platforms" "all" {}
@@ -0,0 +1,24 @@
+resource "azure-github-runners_self_hosted_runner" "linux" {
+  name            = "runner-01"
+  readonly_labels = ["self-hosted", "Linux"]
+  labels          = ["azure"]
+}
+
+# Render cloud-init that installs the runner and starts it as a systemd service
+data "azure-github-runners_runner_bootstrap" "linux" {
+  encoded_jit_config = azure-github-runners_self_hosted_runner.linux.encoded_jit_config
+  os                 = "linux"
+  architecture       = "x64"
+  packages           = ["jq", "unzip"]
+
+  pre_job_script = <<-EOT
+    #!/bin/bash
+    echo "Starting job $GITHUB_JOB"
+  EOT
+}
+
+# Pass data.azure-github-runners_runner_bootstrap.linux.rendered_base64 as the VM custom_data
+output "custom_data" {
+  value     = data.azure-github-runners_runner_bootstrap.linux.rendered_base64
+  sensitive = true
+}
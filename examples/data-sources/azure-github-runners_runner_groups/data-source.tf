@@ -0,0 +1,10 @@
+# Retrieve every runner group in the organization
+data "azure-github-runners_runner_groups" "all" {}
+
+# Retrieve the team runner groups that a given repository can use
+data "azure-github-runners_runner_groups" "platform" {
+  name_regex = "^platform-"
+  visibility = "selected"
+  default    = false
+  repository = "my-repo"
+}
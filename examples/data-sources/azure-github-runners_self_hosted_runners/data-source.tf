@@ -0,0 +1,17 @@
+# Find idle offline Linux runners labelled for draining
+data "azure-github-runners_self_hosted_runners" "draining" {
+  status     = "offline"
+  busy       = false
+  os         = "Linux"
+  labels_all = ["draining"]
+}
+
+# List every runner in a group whose name starts with "ci-"
+data "azure-github-runners_self_hosted_runners" "ci" {
+  runner_group_id = 123
+  name_regex      = "^ci-"
+}
+
+output "draining_runner_ids" {
+  value = data.azure-github-runners_self_hosted_runners.draining.runners[*].id
+}
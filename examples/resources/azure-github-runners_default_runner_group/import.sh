@@ -0,0 +1,3 @@
+# The Default runner group can be imported by specifying its runner group ID.
+# Its settings at import time are captured and restored on destroy.
+terraform import azure-github-runners_default_runner_group.default 1
@@ -0,0 +1,15 @@
+data "azure-github-runners_network_configuration" "main" {
+  name = "production-network-config"
+}
+
+# Take over the organization's Default runner group and lock it down
+resource "azure-github-runners_default_runner_group" "default" {
+  visibility                 = "selected"
+  selected_repository_ids    = [123456789]
+  allows_public_repositories = false
+
+  network_configuration_id = data.azure-github-runners_network_configuration.main.id
+
+  # Put back the settings captured at adoption time on destroy
+  restore_on_destroy = true
+}
@@ -0,0 +1,2 @@
+# Hosted runner can be imported by specifying the hosted runner ID
+terraform import azure-github-runners_hosted_runner.linux 5
@@ -0,0 +1,42 @@
+resource "azure-github-runners_runner_group" "larger" {
+  name                     = "larger-runners"
+  visibility               = "all"
+  network_configuration_id = azure-github-runners_network_configuration.main.id
+}
+
+# Create an Azure-networked GitHub-hosted larger runner
+resource "azure-github-runners_hosted_runner" "linux" {
+  name            = "linux-4-core"
+  runner_group_id = azure-github-runners_runner_group.larger.id
+  size            = "4-core"
+  maximum_runners = 20
+
+  image {
+    id     = "ubuntu-latest"
+    source = "github"
+  }
+
+  public_ip_enabled = false
+
+  timeouts {
+    create = "45m"
+  }
+}
+
+# Run an organization custom image and follow its newest version
+data "azure-github-runners_hosted_runner_custom_image" "toolchain" {
+  name = "build-toolchain"
+}
+
+resource "azure-github-runners_hosted_runner" "toolchain" {
+  name            = "linux-toolchain"
+  runner_group_id = azure-github-runners_runner_group.larger.id
+  size            = "8-core"
+  platform        = "linux-x64"
+
+  image {
+    id      = data.azure-github-runners_hosted_runner_custom_image.toolchain.id
+    source  = "custom"
+    version = "latest"
+  }
+}
@@ -1,2 +1,5 @@
 # Network configuration can be imported by specifying the network configuration ID
 terraform import azure-github-runners_network_configuration.main 23456789ABDCEF1
+
+# It can also be imported by name, which must match exactly one network configuration
+terraform import azure-github-runners_network_configuration.main name:production-network-config
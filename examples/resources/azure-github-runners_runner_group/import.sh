@@ -1,2 +1,5 @@
 # Runner group can be imported by specifying the runner group ID
 terraform import azure-github-runners_runner_group.production 123
+
+# It can also be imported by name, which must match exactly one runner group
+terraform import azure-github-runners_runner_group.production name:production-runners
@@ -14,9 +14,32 @@ resource "azure-github-runners_runner_group" "production" {
 
   allows_public_repositories = false
   restricted_to_workflows    = true
-  selected_workflows = [
-    "my-org/my-repo/.github/workflows/deploy.yaml@refs/heads/main"
-  ]
+
+  selected_workflow {
+    repository = "my-org/my-repo"
+    path       = ".github/workflows/deploy.yaml"
+    ref        = "main"
+  }
 
   network_configuration_id = azure-github-runners_network_configuration.main.id
+
+  # Move runners to a quarantine group instead of letting GitHub put them in Default
+  on_destroy           = "move_to"
+  move_to_runner_group = "quarantine"
+}
+
+# Give every private repository tagged for GPU workloads access to a runner group
+resource "azure-github-runners_runner_group" "gpu" {
+  name       = "gpu-runners"
+  visibility = "selected"
+
+  repository_selector {
+    topics     = ["machine-learning"]
+    visibility = ["private", "internal"]
+
+    custom_property {
+      name   = "runner-tier"
+      values = ["gpu"]
+    }
+  }
 }
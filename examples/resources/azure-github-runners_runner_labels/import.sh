@@ -0,0 +1,2 @@
+# Runner labels can be imported by specifying the runner ID, adopting all of its custom labels
+terraform import azure-github-runners_runner_labels.team 456
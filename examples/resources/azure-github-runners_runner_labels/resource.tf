@@ -0,0 +1,13 @@
+# Let Terraform own only the labels it declares
+resource "azure-github-runners_self_hosted_runner" "main" {
+  name            = "runner-01"
+  readonly_labels = ["self-hosted", "Linux"]
+  labels          = ["production"]
+  authoritative   = false
+}
+
+# Add team labels without removing labels set by the autoscaler, such as "draining"
+resource "azure-github-runners_runner_labels" "team" {
+  runner_id = azure-github-runners_self_hosted_runner.main.id
+  labels    = ["team-platform", "gpu"]
+}
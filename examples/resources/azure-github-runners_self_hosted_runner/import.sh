@@ -1,2 +1,5 @@
 # Self-hosted runner can be imported by specifying the runner ID
 terraform import azure-github-runners_self_hosted_runner.main 456
+
+# It can also be imported by name, which must match exactly one self-hosted runner
+terraform import azure-github-runners_self_hosted_runner.main name:runner-01
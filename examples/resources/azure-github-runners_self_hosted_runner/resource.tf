@@ -27,3 +27,43 @@ resource "azure-github-runners_self_hosted_runner" "main" {
 
   work_folder = "_work"
 }
+
+# Wait for a runner whose machine is provisioned outside this configuration
+resource "azure-github-runners_self_hosted_runner" "prebaked" {
+  name            = "runner-02"
+  runner_group_id = azure-github-runners_runner_group.production.id
+  readonly_labels = ["self-hosted", "Linux"]
+  labels          = ["prebaked"]
+
+  wait_for_online = true
+
+  # Let a running job finish before the runner is removed
+  deletion_policy = "wait"
+
+  timeouts {
+    create = "15m"
+    delete = "1h"
+  }
+}
+
+# Regenerate the JIT configuration when the VM image changes or after 30 days,
+# registering the new runner before the old one is removed
+variable "runner_image_version" {
+  type = string
+}
+
+resource "azure-github-runners_self_hosted_runner" "rotated" {
+  name_prefix     = "rotated"
+  runner_group_id = azure-github-runners_runner_group.production.id
+  readonly_labels = ["self-hosted", "Linux"]
+  labels          = ["rotated"]
+
+  keepers = {
+    image_version = var.runner_image_version
+  }
+  max_age = "720h"
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
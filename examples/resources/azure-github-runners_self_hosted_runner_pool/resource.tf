@@ -0,0 +1,18 @@
+resource "azure-github-runners_runner_group" "ephemeral" {
+  name       = "ephemeral-runners"
+  visibility = "all"
+}
+
+# Create 40 runners, at most 10 at a time
+resource "azure-github-runners_self_hosted_runner_pool" "ephemeral" {
+  name_prefix     = "ephemeral"
+  size            = 40
+  runner_group_id = azure-github-runners_runner_group.ephemeral.id
+  labels          = ["ephemeral", "linux"]
+  parallelism     = 10
+}
+
+# Hand each JIT configuration to one machine
+output "runner_names" {
+  value = azure-github-runners_self_hosted_runner_pool.ephemeral.runners[*].name
+}
@@ -0,0 +1,561 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourceHostedRunner() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manages GitHub-hosted larger runners for the organization.",
+		CreateContext: resourceHostedRunnerCreate,
+		ReadContext:   resourceHostedRunnerRead,
+		UpdateContext: resourceHostedRunnerUpdate,
+		DeleteContext: resourceHostedRunnerDelete,
+		CustomizeDiff: resourceHostedRunnerCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the hosted runner",
+			},
+			"image": {
+				Type:        schema.TypeList,
+				Required:    true,
+				MaxItems:    1,
+				Description: "Image the hosted runner is created from",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Required:    true,
+							ForceNew:    true,
+							Description: "ID of the image, e.g. `ubuntu-latest`",
+						},
+						"source": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							ForceNew:     true,
+							Default:      "github",
+							ValidateFunc: validation.StringInSlice([]string{"github", "partner", "custom"}, false),
+							Description:  "Source of the image: `github`, `partner` or `custom`",
+						},
+						"version": {
+							Type:         schema.TypeString,
+							Optional:     true,
+							Default:      "latest",
+							ValidateFunc: validation.StringIsNotEmpty,
+							Description:  "Version of a custom image to run, or `latest` to follow the newest version. Changing it updates the runner in place",
+						},
+					},
+				},
+			},
+			"size": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Machine size of the hosted runner, e.g. `4-core`",
+			},
+			"runner_group_id": {
+				Type:        schema.TypeInt,
+				Required:    true,
+				Description: "ID of the runner group to add the hosted runner to",
+			},
+			"maximum_runners": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Computed:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum number of runners to scale up to",
+			},
+			"public_ip_enabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether the hosted runner is assigned static public IP addresses",
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "Platform of the hosted runner, e.g. `linux-x64`. When set, the plan fails if the image is built for a different platform",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the hosted runner",
+			},
+			"image_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Image version the hosted runner runs. For custom images following `latest` this changes when a new version is published",
+			},
+			"image_size_gb": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the runner image in GB",
+			},
+			"machine_size_details": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Hardware details of the machine size",
+				Elem:        hostedRunnerMachineSpecResource(),
+			},
+			"public_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Static public IP ranges assigned to the hosted runner",
+				Elem:        hostedRunnerPublicIPResource(),
+			},
+			"last_active_on": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the hosted runner was last active",
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunner() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves a GitHub-hosted larger runner by name.",
+		ReadContext: dataSourceHostedRunnerRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Name of the hosted runner",
+			},
+			"image": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Image the hosted runner is created from",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"size": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Machine size of the hosted runner",
+			},
+			"runner_group_id": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "ID of the runner group the hosted runner belongs to",
+			},
+			"maximum_runners": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of runners to scale up to",
+			},
+			"public_ip_enabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the hosted runner is assigned static public IP addresses",
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Platform of the hosted runner, e.g. `linux-x64`",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the hosted runner",
+			},
+			"image_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Image version the hosted runner runs",
+			},
+			"image_size_gb": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Size of the runner image in GB",
+			},
+			"machine_size_details": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Hardware details of the machine size",
+				Elem:        hostedRunnerMachineSpecResource(),
+			},
+			"public_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Static public IP ranges assigned to the hosted runner",
+				Elem:        hostedRunnerPublicIPResource(),
+			},
+			"last_active_on": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the hosted runner was last active",
+			},
+		},
+	}
+}
+
+func hostedRunnerPublicIPResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"enabled": {
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+			"prefix": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"length": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func hostedRunnerMachineSpecResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"id": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"cpu_cores": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"memory_gb": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+			"storage_gb": {
+				Type:     schema.TypeInt,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceHostedRunnerCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	image := d.Get("image").([]interface{})[0].(map[string]interface{})
+	req := &CreateHostedRunnerRequest{
+		Name: d.Get("name").(string),
+		Image: HostedRunnerImage{
+			ID:     image["id"].(string),
+			Source: image["source"].(string),
+		},
+		Size:           d.Get("size").(string),
+		RunnerGroupID:  d.Get("runner_group_id").(int),
+		MaximumRunners: d.Get("maximum_runners").(int),
+		EnableStaticIP: d.Get("public_ip_enabled").(bool),
+	}
+	if req.Image.Source == "custom" {
+		// Pin the version resolved at plan time so the runner matches the plan
+		req.Image.Version = d.Get("image_version").(string)
+		if req.Image.Version == "" {
+			req.Image.Version = image["version"].(string)
+		}
+	}
+
+	var result HostedRunner
+	err := client.Post(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners", client.organization), req, &result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(strconv.Itoa(result.ID))
+
+	if err := waitForHostedRunnerReady(ctx, client, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+		return diag.Errorf("hosted runner %s did not become ready: %v", d.Id(), err)
+	}
+
+	return resourceHostedRunnerRead(ctx, d, m)
+}
+
+func resourceHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	hostedRunnerID := d.Id()
+	var runner HostedRunner
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/%s", client.organization, hostedRunnerID), &runner)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Keep following latest instead of pinning to the version reported by the API
+	version := d.Get("image.0.version").(string)
+	setHostedRunnerData(d, &runner)
+	if version == "" || version == "latest" {
+		d.Set("image", []interface{}{map[string]interface{}{
+			"id":      runner.ImageDetails.ID,
+			"source":  runner.ImageDetails.Source,
+			"version": "latest",
+		}})
+	}
+
+	return nil
+}
+
+func resourceHostedRunnerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	hostedRunnerID := d.Id()
+	req := &UpdateHostedRunnerRequest{
+		Name:           d.Get("name").(string),
+		RunnerGroupID:  d.Get("runner_group_id").(int),
+		MaximumRunners: d.Get("maximum_runners").(int),
+		EnableStaticIP: boolPtr(d.Get("public_ip_enabled").(bool)),
+	}
+	if d.HasChange("image_version") && d.Get("image.0.source").(string) == "custom" {
+		req.ImageVersion = d.Get("image_version").(string)
+	}
+
+	var result HostedRunner
+	err := client.Patch(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/%s", client.organization, hostedRunnerID), req, &result)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := waitForHostedRunnerReady(ctx, client, hostedRunnerID, d.Timeout(schema.TimeoutUpdate)); err != nil {
+		return diag.Errorf("hosted runner %s did not become ready: %v", hostedRunnerID, err)
+	}
+
+	return resourceHostedRunnerRead(ctx, d, m)
+}
+
+func resourceHostedRunnerDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	hostedRunnerID := d.Id()
+	err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/%s", client.organization, hostedRunnerID), nil)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Deletion is asynchronous, wait until the runner is gone
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"Ready", "Provisioning", "Shutdown", "Deleting", "Stuck"},
+		Target:  []string{"Deleted"},
+		Refresh: func() (interface{}, string, error) {
+			var runner HostedRunner
+			err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/%s", client.organization, hostedRunnerID), &runner)
+			if isNotFound(err) {
+				return &runner, "Deleted", nil
+			}
+			if err != nil {
+				return nil, "", err
+			}
+			return &runner, runner.Status, nil
+		},
+		Timeout:    d.Timeout(schema.TimeoutDelete),
+		MinTimeout: 10 * time.Second,
+	}
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return diag.Errorf("hosted runner %s was not deleted: %v", hostedRunnerID, err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func dataSourceHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	name := d.Get("name").(string)
+
+	runners, err := listHostedRunners(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var foundRunner *HostedRunner
+	for _, runner := range runners {
+		if runner.Name == name {
+			foundRunner = &runner
+			break
+		}
+	}
+
+	if foundRunner == nil {
+		return diag.Errorf("Hosted runner with name '%s' not found", name)
+	}
+
+	d.SetId(strconv.Itoa(foundRunner.ID))
+	setHostedRunnerData(d, foundRunner)
+
+	return nil
+}
+
+// waitForHostedRunnerReady polls the hosted runner until its status is Ready
+func waitForHostedRunnerReady(ctx context.Context, client *Client, hostedRunnerID string, timeout time.Duration) error {
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"Provisioning", "Shutdown"},
+		Target:  []string{"Ready"},
+		Refresh: func() (interface{}, string, error) {
+			var runner HostedRunner
+			err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/%s", client.organization, hostedRunnerID), &runner)
+			if err != nil {
+				return nil, "", err
+			}
+			return &runner, runner.Status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 10 * time.Second,
+	}
+
+	_, err := stateConf.WaitForStateContext(ctx)
+	return err
+}
+
+// resourceHostedRunnerCustomizeDiff resolves the custom image version to run, so a newly
+// published version shows up as a plan diff, and checks the image platform against the
+// configured platform
+func resourceHostedRunnerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client := m.(*Client)
+
+	if !d.NewValueKnown("image") {
+		return nil
+	}
+	images := d.Get("image").([]interface{})
+	if len(images) == 0 || images[0] == nil {
+		return nil
+	}
+	image := images[0].(map[string]interface{})
+	imageID := image["id"].(string)
+	source := image["source"].(string)
+	version := image["version"].(string)
+
+	var imagePlatform string
+	if source == "custom" {
+		customImage, err := getHostedRunnerCustomImage(ctx, client, imageID)
+		if err != nil {
+			return err
+		}
+		imagePlatform = customImage.Platform
+
+		resolved := version
+		if version == "latest" {
+			resolved = customImage.LatestVersion
+		} else {
+			imageVersion, err := getHostedRunnerCustomImageVersion(ctx, client, imageID, version)
+			if err != nil {
+				return err
+			}
+			if imageVersion.State != "Ready" {
+				return fmt.Errorf("version %s of custom image %s is %s, not Ready", version, imageID, imageVersion.State)
+			}
+		}
+
+		if resolved != d.Get("image_version").(string) {
+			if err := d.SetNew("image_version", resolved); err != nil {
+				return err
+			}
+		}
+	} else if version != "latest" {
+		return fmt.Errorf("image.version can only be pinned for custom images")
+	}
+
+	if d.GetRawConfig().GetAttr("platform").IsNull() || !d.NewValueKnown("platform") {
+		return nil
+	}
+	platform := d.Get("platform").(string)
+
+	if imagePlatform == "" {
+		endpoint := "github-owned"
+		if source == "partner" {
+			endpoint = "partner"
+		}
+		var imageList HostedRunnerImageList
+		err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/%s", client.organization, endpoint), &imageList)
+		if err != nil {
+			return err
+		}
+		for _, i := range imageList.Images {
+			if i.ID == imageID {
+				imagePlatform = i.Platform
+				break
+			}
+		}
+	}
+
+	if imagePlatform != "" && imagePlatform != platform {
+		return fmt.Errorf("image %s is built for platform %s, not %s", imageID, imagePlatform, platform)
+	}
+
+	return nil
+}
+
+func listHostedRunners(ctx context.Context, client *Client) ([]HostedRunner, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/actions/hosted-runners", client.organization), func(l *HostedRunnerList) (int, []HostedRunner) {
+		return l.TotalCount, l.Runners
+	})
+}
+
+func setHostedRunnerData(d *schema.ResourceData, runner *HostedRunner) {
+	d.Set("name", runner.Name)
+	d.Set("image", []interface{}{map[string]interface{}{
+		"id":      runner.ImageDetails.ID,
+		"source":  runner.ImageDetails.Source,
+		"version": runner.ImageDetails.Version,
+	}})
+	d.Set("size", runner.MachineSizeDetails.ID)
+	d.Set("runner_group_id", runner.RunnerGroupID)
+	d.Set("maximum_runners", runner.MaximumRunners)
+	d.Set("public_ip_enabled", runner.PublicIPEnabled)
+	d.Set("platform", runner.Platform)
+	d.Set("status", runner.Status)
+	d.Set("image_version", runner.ImageDetails.Version)
+	d.Set("image_size_gb", runner.ImageDetails.SizeGB)
+	d.Set("machine_size_details", []interface{}{map[string]interface{}{
+		"id":         runner.MachineSizeDetails.ID,
+		"cpu_cores":  runner.MachineSizeDetails.CPUCores,
+		"memory_gb":  runner.MachineSizeDetails.MemoryGB,
+		"storage_gb": runner.MachineSizeDetails.StorageGB,
+	}})
+	d.Set("last_active_on", runner.LastActiveOn)
+
+	publicIPs := make([]map[string]interface{}, len(runner.PublicIPs))
+	for i, ip := range runner.PublicIPs {
+		publicIPs[i] = map[string]interface{}{
+			"enabled": ip.Enabled,
+			"prefix":  ip.Prefix,
+			"length":  ip.Length,
+		}
+	}
+	d.Set("public_ips", publicIPs)
+}
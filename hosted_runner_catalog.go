@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceHostedRunnerImages() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves the GitHub-owned or partner images available to GitHub-hosted runners.",
+		ReadContext: dataSourceHostedRunnerImagesRead,
+		Schema: map[string]*schema.Schema{
+			"source": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "github-owned",
+				ValidateFunc: validation.StringInSlice([]string{"github-owned", "partner"}, false),
+				Description:  "Which images to list: `github-owned` or `partner`",
+			},
+			"platform": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return images for this platform, e.g. `linux-x64`",
+			},
+			"images": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of matching images",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"platform": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"size_gb": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"source": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunnerMachineSizes() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves the machine sizes available to GitHub-hosted runners.",
+		ReadContext: dataSourceHostedRunnerMachineSizesRead,
+		Schema: map[string]*schema.Schema{
+			"min_cpu_cores": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only return machine sizes with at least this many CPU cores",
+			},
+			"min_memory_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only return machine sizes with at least this much memory in GB",
+			},
+			"min_storage_gb": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Only return machine sizes with at least this much storage in GB",
+			},
+			"machine_sizes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of matching machine sizes",
+				Elem:        hostedRunnerMachineSpecResource(),
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunnerPlatforms() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves the platforms available to GitHub-hosted runners.",
+		ReadContext: dataSourceHostedRunnerPlatformsRead,
+		Schema: map[string]*schema.Schema{
+			"platforms": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "List of available platforms, e.g. `linux-x64`",
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunnerLimits() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves the GitHub-hosted runner limits of the organization along with the current usage by existing hosted runners.",
+		ReadContext: dataSourceHostedRunnerLimitsRead,
+		Schema: map[string]*schema.Schema{
+			"public_ips_maximum": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Maximum number of static public IP addresses the organization can use",
+			},
+			"public_ips_current_usage": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of static public IP addresses currently in use",
+			},
+			"hosted_runners_count": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Number of hosted runners in the organization",
+			},
+			"maximum_runners_total": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Sum of `maximum_runners` across the organization's hosted runners",
+			},
+			"maximum_runners_quota": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Concurrency quota to check `maximum_runners_total` against. The API does not report the organization's hosted runner quota, so set it from the concurrency limit of the organization's plan",
+			},
+			"exceeds_quota": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether `maximum_runners_total` is above `maximum_runners_quota`. Always false when no quota is set",
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunnerImagesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	source := d.Get("source").(string)
+	platform := d.Get("platform").(string)
+
+	var imageList HostedRunnerImageList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/%s", client.organization, source), &imageList)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	images := make([]map[string]interface{}, 0, len(imageList.Images))
+	for _, image := range imageList.Images {
+		if platform != "" && image.Platform != platform {
+			continue
+		}
+		images = append(images, map[string]interface{}{
+			"id":           image.ID,
+			"platform":     image.Platform,
+			"size_gb":      image.SizeGB,
+			"display_name": image.DisplayName,
+			"source":       image.Source,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("hosted-runner-images-%s", source))
+	d.Set("images", images)
+
+	return nil
+}
+
+func dataSourceHostedRunnerMachineSizesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	minCPUCores := d.Get("min_cpu_cores").(int)
+	minMemoryGB := d.Get("min_memory_gb").(int)
+	minStorageGB := d.Get("min_storage_gb").(int)
+
+	var specList HostedRunnerMachineSpecList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/machine-sizes", client.organization), &specList)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	machineSizes := make([]map[string]interface{}, 0, len(specList.MachineSpecs))
+	for _, spec := range specList.MachineSpecs {
+		if spec.CPUCores < minCPUCores || spec.MemoryGB < minMemoryGB || spec.StorageGB < minStorageGB {
+			continue
+		}
+		machineSizes = append(machineSizes, map[string]interface{}{
+			"id":         spec.ID,
+			"cpu_cores":  spec.CPUCores,
+			"memory_gb":  spec.MemoryGB,
+			"storage_gb": spec.StorageGB,
+		})
+	}
+
+	d.SetId("hosted-runner-machine-sizes")
+	d.Set("machine_sizes", machineSizes)
+
+	return nil
+}
+
+func dataSourceHostedRunnerPlatformsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	var platformList HostedRunnerPlatformList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/platforms", client.organization), &platformList)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("hosted-runner-platforms")
+	d.Set("platforms", platformList.Platforms)
+
+	return nil
+}
+
+func dataSourceHostedRunnerLimitsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	var limits HostedRunnerLimits
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/limits", client.organization), &limits)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// The API does not report runner usage, so total it from the existing runners
+	runners, err := listHostedRunners(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	maximumRunnersTotal := 0
+	for _, runner := range runners {
+		maximumRunnersTotal += runner.MaximumRunners
+	}
+
+	d.SetId("hosted-runner-limits")
+	d.Set("public_ips_maximum", limits.PublicIPs.Maximum)
+	d.Set("public_ips_current_usage", limits.PublicIPs.CurrentUsage)
+	d.Set("hosted_runners_count", len(runners))
+	d.Set("maximum_runners_total", maximumRunnersTotal)
+
+	quota := d.Get("maximum_runners_quota").(int)
+	d.Set("exceeds_quota", quota > 0 && maximumRunnersTotal > quota)
+
+	return nil
+}
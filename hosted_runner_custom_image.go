@@ -0,0 +1,247 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func dataSourceHostedRunnerCustomImages() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves the organization's custom images for GitHub-hosted runners.",
+		ReadContext: dataSourceHostedRunnerCustomImagesRead,
+		Schema: map[string]*schema.Schema{
+			"platform": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return images for this platform, e.g. `linux-x64`",
+			},
+			"images": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of matching custom images",
+				Elem: &schema.Resource{
+					Schema: hostedRunnerCustomImageSchema(),
+				},
+			},
+		},
+	}
+}
+
+func dataSourceHostedRunnerCustomImage() *schema.Resource {
+	s := hostedRunnerCustomImageSchema()
+	s["id"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"id", "name"},
+		Description:  "ID of the custom image",
+	}
+	s["name"] = &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		Computed:     true,
+		ExactlyOneOf: []string{"id", "name"},
+		Description:  "Name of the custom image",
+	}
+	s["versions"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Description: "Versions of the custom image",
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"version": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"state": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"size_gb": {
+					Type:     schema.TypeInt,
+					Computed: true,
+				},
+				"created_on": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+				"state_details": {
+					Type:     schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Description: "Retrieves a custom image for GitHub-hosted runners by ID or name, along with its versions.",
+		ReadContext: dataSourceHostedRunnerCustomImageRead,
+		Schema:      s,
+	}
+}
+
+func hostedRunnerCustomImageSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"id": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"name": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"platform": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"source": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"state": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"latest_version": {
+			Type:     schema.TypeString,
+			Computed: true,
+		},
+		"versions_count": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+		"total_versions_size": {
+			Type:     schema.TypeInt,
+			Computed: true,
+		},
+	}
+}
+
+func dataSourceHostedRunnerCustomImagesRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	platform := d.Get("platform").(string)
+
+	customImages, err := listHostedRunnerCustomImages(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	images := make([]map[string]interface{}, 0, len(customImages))
+	for _, image := range customImages {
+		if platform != "" && image.Platform != platform {
+			continue
+		}
+		images = append(images, flattenHostedRunnerCustomImage(&image))
+	}
+
+	d.SetId("hosted-runner-custom-images")
+	d.Set("images", images)
+
+	return nil
+}
+
+func dataSourceHostedRunnerCustomImageRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	var image *HostedRunnerCustomImage
+	if id := d.Get("id").(string); id != "" {
+		var err error
+		image, err = getHostedRunnerCustomImage(ctx, client, id)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		name := d.Get("name").(string)
+
+		customImages, err := listHostedRunnerCustomImages(ctx, client)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		for _, customImage := range customImages {
+			if customImage.Name == name {
+				image = &customImage
+				break
+			}
+		}
+		if image == nil {
+			return diag.Errorf("Custom image with name '%s' not found", name)
+		}
+	}
+
+	var versionList HostedRunnerCustomImageVersionList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/custom/%d/versions", client.organization, image.ID), &versionList)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	versions := make([]map[string]interface{}, len(versionList.ImageVersions))
+	for i, version := range versionList.ImageVersions {
+		versions[i] = map[string]interface{}{
+			"version":       version.Version,
+			"state":         version.State,
+			"size_gb":       version.SizeGB,
+			"created_on":    version.CreatedOn,
+			"state_details": version.StateDetails,
+		}
+	}
+
+	d.SetId(strconv.Itoa(image.ID))
+	for k, v := range flattenHostedRunnerCustomImage(image) {
+		d.Set(k, v)
+	}
+	d.Set("versions", versions)
+
+	return nil
+}
+
+func listHostedRunnerCustomImages(ctx context.Context, client *Client) ([]HostedRunnerCustomImage, error) {
+	var imageList HostedRunnerCustomImageList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/custom", client.organization), &imageList)
+	if err != nil {
+		return nil, err
+	}
+	return imageList.Images, nil
+}
+
+func getHostedRunnerCustomImage(ctx context.Context, client *Client, imageID string) (*HostedRunnerCustomImage, error) {
+	var image HostedRunnerCustomImage
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/custom/%s", client.organization, url.PathEscape(imageID)), &image)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("custom image %s not found", imageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+func getHostedRunnerCustomImageVersion(ctx context.Context, client *Client, imageID, version string) (*HostedRunnerCustomImageVersion, error) {
+	var imageVersion HostedRunnerCustomImageVersion
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/hosted-runners/images/custom/%s/versions/%s", client.organization, url.PathEscape(imageID), url.PathEscape(version)), &imageVersion)
+	if isNotFound(err) {
+		return nil, fmt.Errorf("version %s of custom image %s not found", version, imageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &imageVersion, nil
+}
+
+func flattenHostedRunnerCustomImage(image *HostedRunnerCustomImage) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                  strconv.Itoa(image.ID),
+		"name":                image.Name,
+		"platform":            image.Platform,
+		"source":              image.Source,
+		"state":               image.State,
+		"latest_version":      image.LatestVersion,
+		"versions_count":      image.VersionsCount,
+		"total_versions_size": image.TotalVersionsSize,
+	}
+}
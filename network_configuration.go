@@ -17,7 +17,7 @@ func resourceNetworkConfiguration() *schema.Resource {
 		UpdateContext: resourceNetworkConfigurationUpdate,
 		DeleteContext: resourceNetworkConfigurationDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughOrName("Network configuration", lookupNetworkConfigurationIDs),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -179,6 +179,28 @@ func dataSourceNetworkConfigurationRead(ctx context.Context, d *schema.ResourceD
 	return nil
 }
 
+// listNetworkConfigurations returns every network configuration in the organization
+func listNetworkConfigurations(ctx context.Context, client *Client) ([]NetworkConfiguration, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/settings/network-configurations", client.organization), func(l *NetworkConfigurationList) (int, []NetworkConfiguration) {
+		return l.TotalCount, l.NetworkConfigurations
+	})
+}
+
+// lookupNetworkConfigurationIDs returns the IDs of the network configurations with the given name
+func lookupNetworkConfigurationIDs(ctx context.Context, client *Client, name string) ([]string, error) {
+	configs, err := listNetworkConfigurations(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, 1)
+	for _, config := range configs {
+		if config.Name == name {
+			ids = append(ids, config.ID)
+		}
+	}
+	return ids, nil
+}
+
 func expandStringList(configured []interface{}) []string {
 	vs := make([]string, 0, len(configured))
 	for _, v := range configured {
@@ -2,6 +2,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
@@ -60,17 +62,31 @@ func Provider() *schema.Provider {
 			},
 		},
 		ResourcesMap: map[string]*schema.Resource{
-			"azure-github-runners_network_configuration": resourceNetworkConfiguration(),
-			"azure-github-runners_runner_group":          resourceRunnerGroup(),
-			"azure-github-runners_self_hosted_runner":    resourceSelfHostedRunner(),
+			"azure-github-runners_network_configuration":   resourceNetworkConfiguration(),
+			"azure-github-runners_runner_group":            resourceRunnerGroup(),
+			"azure-github-runners_default_runner_group":    resourceDefaultRunnerGroup(),
+			"azure-github-runners_self_hosted_runner":      resourceSelfHostedRunner(),
+			"azure-github-runners_self_hosted_runner_pool": resourceSelfHostedRunnerPool(),
+			"azure-github-runners_runner_labels":           resourceRunnerLabels(),
+			"azure-github-runners_hosted_runner":           resourceHostedRunner(),
 		},
 		DataSourcesMap: map[string]*schema.Resource{
-			"azure-github-runners_network_configuration": dataSourceNetworkConfiguration(),
-			"azure-github-runners_runner_group":          dataSourceRunnerGroup(),
-			"azure-github-runners_self_hosted_runner":    dataSourceSelfHostedRunner(),
-			"azure-github-runners_runner_applications":   dataSourceRunnerApplications(),
-			"azure-github-runners_registration_token":    dataSourceRegistrationToken(),
-			"azure-github-runners_remove_token":          dataSourceRemoveToken(),
+			"azure-github-runners_network_configuration":       dataSourceNetworkConfiguration(),
+			"azure-github-runners_runner_group":                dataSourceRunnerGroup(),
+			"azure-github-runners_runner_groups":               dataSourceRunnerGroups(),
+			"azure-github-runners_self_hosted_runner":          dataSourceSelfHostedRunner(),
+			"azure-github-runners_self_hosted_runners":         dataSourceSelfHostedRunners(),
+			"azure-github-runners_hosted_runner":               dataSourceHostedRunner(),
+			"azure-github-runners_hosted_runner_images":        dataSourceHostedRunnerImages(),
+			"azure-github-runners_hosted_runner_custom_images": dataSourceHostedRunnerCustomImages(),
+			"azure-github-runners_hosted_runner_custom_image":  dataSourceHostedRunnerCustomImage(),
+			"azure-github-runners_hosted_runner_machine_sizes": dataSourceHostedRunnerMachineSizes(),
+			"azure-github-runners_hosted_runner_platforms":     dataSourceHostedRunnerPlatforms(),
+			"azure-github-runners_hosted_runner_limits":        dataSourceHostedRunnerLimits(),
+			"azure-github-runners_runner_applications":         dataSourceRunnerApplications(),
+			"azure-github-runners_runner_bootstrap":            dataSourceRunnerBootstrap(),
+			"azure-github-runners_registration_token":          dataSourceRegistrationToken(),
+			"azure-github-runners_remove_token":                dataSourceRemoveToken(),
 		},
 		ConfigureContextFunc: providerConfigure,
 	}
@@ -139,3 +155,32 @@ func (c *Config) Client() (*Client, error) {
 	}
 	return NewClient(c.Token, c.BaseURL, c.Organization, c.Insecure)
 }
+
+// importNamePrefix marks an import ID as a name to look up rather than an ID
+const importNamePrefix = "name:"
+
+// importStatePassthroughOrName returns an importer that accepts either an ID or
+// name:<name>. lookup returns the IDs of all objects with the name, and the import
+// fails unless exactly one matches.
+func importStatePassthroughOrName(kind string, lookup func(ctx context.Context, client *Client, name string) ([]string, error)) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		name, ok := strings.CutPrefix(d.Id(), importNamePrefix)
+		if !ok {
+			return schema.ImportStatePassthroughContext(ctx, d, m)
+		}
+
+		ids, err := lookup(ctx, m.(*Client), name)
+		if err != nil {
+			return nil, err
+		}
+		switch len(ids) {
+		case 0:
+			return nil, fmt.Errorf("%s with name '%s' not found", kind, name)
+		case 1:
+			d.SetId(ids[0])
+			return []*schema.ResourceData{d}, nil
+		default:
+			return nil, fmt.Errorf("%s name '%s' is ambiguous, it matches IDs %s; import by ID instead", kind, name, strings.Join(ids, ", "))
+		}
+	}
+}
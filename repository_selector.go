@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// RepositorySelector selects organization repositories by their attributes.
+// All configured criteria must match for a repository to be selected.
+type RepositorySelector struct {
+	Topics           []string
+	NameRegex        *regexp.Regexp
+	Visibilities     []string
+	CustomProperties map[string][]string
+	IncludeArchived  bool
+}
+
+func repositorySelectorResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"topics": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Topics a repository must all have to be selected",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression the repository name must match",
+			},
+			"visibility": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validation.StringInSlice([]string{"public", "private", "internal"}, false)},
+				Description: "Repository visibilities to select: `public`, `private` or `internal`",
+			},
+			"custom_property": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "Organization custom property a repository must have one of the given values for",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Required:    true,
+							Description: "Name of the custom property",
+						},
+						"values": {
+							Type:        schema.TypeSet,
+							Required:    true,
+							MinItems:    1,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+							Description: "Accepted values of the custom property",
+						},
+					},
+				},
+			},
+			"include_archived": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether archived repositories can be selected",
+			},
+		},
+	}
+}
+
+func expandRepositorySelector(raw map[string]interface{}) (*RepositorySelector, error) {
+	selector := &RepositorySelector{
+		Topics:           expandStringList(raw["topics"].(*schema.Set).List()),
+		Visibilities:     expandStringList(raw["visibility"].(*schema.Set).List()),
+		CustomProperties: make(map[string][]string),
+		IncludeArchived:  raw["include_archived"].(bool),
+	}
+
+	if v := raw["name_regex"].(string); v != "" {
+		nameRegex, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("repository_selector.name_regex: %v", err)
+		}
+		selector.NameRegex = nameRegex
+	}
+
+	for _, p := range raw["custom_property"].([]interface{}) {
+		property := p.(map[string]interface{})
+		selector.CustomProperties[property["name"].(string)] = expandStringList(property["values"].(*schema.Set).List())
+	}
+
+	if len(selector.Topics) == 0 && selector.NameRegex == nil && len(selector.Visibilities) == 0 && len(selector.CustomProperties) == 0 {
+		return nil, fmt.Errorf("repository_selector must set at least one of topics, name_regex, visibility or custom_property")
+	}
+
+	return selector, nil
+}
+
+// Matches reports whether repo satisfies every criterion of the selector.
+// properties holds the repository's custom property values keyed by property name.
+func (s *RepositorySelector) Matches(repo Repository, properties map[string][]string) bool {
+	if repo.Archived && !s.IncludeArchived {
+		return false
+	}
+	if s.NameRegex != nil && !s.NameRegex.MatchString(repo.Name) {
+		return false
+	}
+	if len(s.Visibilities) > 0 && !containsString(s.Visibilities, repo.Visibility) {
+		return false
+	}
+	for _, topic := range s.Topics {
+		if !containsString(repo.Topics, topic) {
+			return false
+		}
+	}
+	for name, accepted := range s.CustomProperties {
+		matched := false
+		for _, value := range properties[name] {
+			if containsString(accepted, value) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// selectRepositoryIDs evaluates the selector against every repository in the organization
+// and returns the sorted IDs of those that match
+func selectRepositoryIDs(ctx context.Context, client *Client, selector *RepositorySelector) ([]int, error) {
+	repositories, err := getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/repos?type=all", client.organization), func(l *[]Repository) (int, []Repository) {
+		return -1, *l
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization repositories: %v", err)
+	}
+
+	properties := make(map[int]map[string][]string)
+	if len(selector.CustomProperties) > 0 {
+		values, err := getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/properties/values", client.organization), func(l *[]RepositoryCustomPropertyValues) (int, []RepositoryCustomPropertyValues) {
+			return -1, *l
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list repository custom property values: %v", err)
+		}
+		for _, v := range values {
+			properties[v.RepositoryID] = v.PropertyValues()
+		}
+	}
+
+	ids := make([]int, 0)
+	for _, repo := range repositories {
+		if selector.Matches(repo, properties[repo.ID]) {
+			ids = append(ids, repo.ID)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// resourceRunnerGroupSelectorCustomizeDiff re-evaluates repository_selector on every plan,
+// so repositories that start or stop matching show up as a membership diff
+func resourceRunnerGroupSelectorCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client := m.(*Client)
+
+	selectors := d.Get("repository_selector").([]interface{})
+	if len(selectors) == 0 || selectors[0] == nil {
+		// Without a selector the list is only what the configuration says
+		if d.GetRawConfig().GetAttr("selected_repository_ids").IsNull() && len(d.Get("selected_repository_ids").([]interface{})) > 0 {
+			return d.SetNew("selected_repository_ids", []int{})
+		}
+		return nil
+	}
+
+	if d.NewValueKnown("visibility") && d.Get("visibility").(string) != "selected" {
+		return fmt.Errorf("repository_selector requires visibility to be 'selected'")
+	}
+
+	if !d.NewValueKnown("repository_selector") {
+		return d.SetNewComputed("selected_repository_ids")
+	}
+
+	selector, err := expandRepositorySelector(selectors[0].(map[string]interface{}))
+	if err != nil {
+		return err
+	}
+
+	ids, err := selectRepositoryIDs(ctx, client, selector)
+	if err != nil {
+		return err
+	}
+	if len(ids) == 0 {
+		return fmt.Errorf("repository_selector did not match any repository")
+	}
+
+	return d.SetNew("selected_repository_ids", ids)
+}
+
+// PropertyValues returns the repository's custom property values keyed by property name
+func (v RepositoryCustomPropertyValues) PropertyValues() map[string][]string {
+	values := make(map[string][]string, len(v.Properties))
+	for _, p := range v.Properties {
+		switch value := p.Value.(type) {
+		case string:
+			values[p.PropertyName] = []string{value}
+		case []interface{}:
+			for _, item := range value {
+				if s, ok := item.(string); ok {
+					values[p.PropertyName] = append(values[p.PropertyName], s)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// runnerBootstrap holds everything needed to render a runner bootstrap script
+type runnerBootstrap struct {
+	EncodedJITConfig string
+	DownloadURL      string
+	Filename         string
+	SHA256Checksum   string
+	InstallDir       string
+	User             string
+	RunAsService     bool
+	Packages         []string
+	PreJobScript     string
+}
+
+var bootstrapTemplateFuncs = template.FuncMap{
+	"sh":   shellQuote,
+	"ps":   powerShellQuote,
+	"yaml": yamlQuote,
+	"b64":  func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+}
+
+// linuxBootstrapScript installs the runner and starts it with the JIT configuration
+var linuxBootstrapScript = template.Must(template.New("bootstrap.sh").Funcs(bootstrapTemplateFuncs).Parse(`#!/bin/bash
+set -euo pipefail
+
+RUNNER_DIR={{ sh .InstallDir }}
+RUNNER_USER={{ sh .User }}
+
+id -u "$RUNNER_USER" >/dev/null 2>&1 || useradd --create-home "$RUNNER_USER"
+mkdir -p "$RUNNER_DIR"
+cd "$RUNNER_DIR"
+
+curl -fsSL -o {{ sh .Filename }} {{ sh .DownloadURL }}
+{{- if .SHA256Checksum }}
+echo {{ sh (printf "%s  %s" .SHA256Checksum .Filename) }} | sha256sum -c -
+{{- end }}
+tar xzf {{ sh .Filename }}
+rm -f {{ sh .Filename }}
+./bin/installdependencies.sh
+{{- if .PreJobScript }}
+echo "ACTIONS_RUNNER_HOOK_JOB_STARTED=$RUNNER_DIR/pre-job.sh" >> .env
+{{- end }}
+chown -R "$RUNNER_USER" "$RUNNER_DIR"
+{{ if .RunAsService -}}
+systemctl daemon-reload
+systemctl enable --now actions-runner.service
+{{- else -}}
+su "$RUNNER_USER" -c 'set -a && . ./.jitconfig.env && set +a && nohup ./run.sh >> runner.log 2>&1 &'
+{{- end }}
+`))
+
+// linuxRunnerUnit runs the runner as a systemd service. The JIT configuration comes from an
+// environment file readable only by its owner, so it does not show up in the command line.
+var linuxRunnerUnit = template.Must(template.New("actions-runner.service").Parse(`[Unit]
+Description=GitHub Actions runner
+After=network-online.target
+Wants=network-online.target
+
+[Service]
+Type=simple
+User={{ .User }}
+WorkingDirectory={{ .InstallDir }}
+EnvironmentFile={{ .InstallDir }}/.jitconfig.env
+ExecStart={{ .InstallDir }}/run.sh
+KillMode=process
+KillSignal=SIGTERM
+TimeoutStopSec=5min
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// linuxCloudConfig writes the runner files and runs the bootstrap script on first boot
+var linuxCloudConfig = template.Must(template.New("cloud-config").Funcs(bootstrapTemplateFuncs).Parse(`#cloud-config
+{{- if .Bootstrap.Packages }}
+packages:
+{{- range .Bootstrap.Packages }}
+  - {{ yaml . }}
+{{- end }}
+{{- end }}
+write_files:
+  - path: {{ yaml (print .Bootstrap.InstallDir "/.jitconfig.env") }}
+    permissions: "0600"
+    encoding: b64
+    content: {{ b64 (print "ACTIONS_RUNNER_INPUT_JITCONFIG=" .Bootstrap.EncodedJITConfig) }}
+{{- if .Bootstrap.PreJobScript }}
+  - path: {{ yaml (print .Bootstrap.InstallDir "/pre-job.sh") }}
+    permissions: "0755"
+    encoding: b64
+    content: {{ b64 .Bootstrap.PreJobScript }}
+{{- end }}
+{{- if .Bootstrap.RunAsService }}
+  - path: /etc/systemd/system/actions-runner.service
+    permissions: "0644"
+    encoding: b64
+    content: {{ b64 .Unit }}
+{{- end }}
+  - path: /usr/local/sbin/actions-runner-bootstrap.sh
+    permissions: "0700"
+    encoding: b64
+    content: {{ b64 .Script }}
+runcmd:
+  - [/usr/local/sbin/actions-runner-bootstrap.sh]
+`))
+
+// windowsBootstrapScript installs the runner and starts it with the JIT configuration
+var windowsBootstrapScript = template.Must(template.New("bootstrap.ps1").Funcs(bootstrapTemplateFuncs).Parse(`$ErrorActionPreference = 'Stop'
+$ProgressPreference = 'SilentlyContinue'
+
+$RunnerDir = {{ ps .InstallDir }}
+New-Item -ItemType Directory -Force -Path $RunnerDir | Out-Null
+Set-Location $RunnerDir
+
+Invoke-WebRequest -UseBasicParsing -Uri {{ ps .DownloadURL }} -OutFile {{ ps .Filename }}
+{{- if .SHA256Checksum }}
+if ((Get-FileHash -Algorithm SHA256 -Path {{ ps .Filename }}).Hash -ne {{ ps .SHA256Checksum }}) {
+    throw 'Runner package checksum mismatch'
+}
+{{- end }}
+Expand-Archive -Path {{ ps .Filename }} -DestinationPath $RunnerDir -Force
+Remove-Item {{ ps .Filename }}
+
+$JITConfigPath = Join-Path $RunnerDir '.jitconfig'
+Set-Content -Path $JITConfigPath -Value {{ ps .EncodedJITConfig }} -NoNewline
+{{- if .PreJobScript }}
+$PreJobScript = Join-Path $RunnerDir 'pre-job.ps1'
+[IO.File]::WriteAllBytes($PreJobScript, [Convert]::FromBase64String({{ ps (b64 .PreJobScript) }}))
+Add-Content -Path (Join-Path $RunnerDir '.env') -Value "ACTIONS_RUNNER_HOOK_JOB_STARTED=$PreJobScript"
+{{- end }}
+{{ if .RunAsService -}}
+icacls $JITConfigPath /inheritance:r /grant:r '*S-1-5-18:F' | Out-Null
+
+# The task reads the JIT configuration when it runs, so it never appears in the task
+# definition, and deletes it since it can only be used once. The task has no trigger and
+# unregisters itself before starting the runner, so it runs once, and has no time limit,
+# so the runner is not stopped while it waits for or runs its job.
+$StartRunner = Join-Path $RunnerDir 'start-runner.ps1'
+Set-Content -Path $StartRunner -Value @'
+$ErrorActionPreference = 'Stop'
+$RunnerDir = Split-Path -Parent $MyInvocation.MyCommand.Path
+$JITConfigPath = Join-Path $RunnerDir '.jitconfig'
+$JITConfig = Get-Content -Raw $JITConfigPath
+Remove-Item $JITConfigPath
+Unregister-ScheduledTask -TaskName 'actions-runner' -Confirm:$false
+$Runner = Start-Process -FilePath (Join-Path $RunnerDir 'run.cmd') -ArgumentList '--jitconfig', $JITConfig -WorkingDirectory $RunnerDir -NoNewWindow -PassThru
+$Runner.WaitForExit()
+'@
+$Action = New-ScheduledTaskAction -Execute 'powershell.exe' -Argument ('-NoProfile -ExecutionPolicy Bypass -File "{0}"' -f $StartRunner) -WorkingDirectory $RunnerDir
+$Settings = New-ScheduledTaskSettingsSet -ExecutionTimeLimit ([TimeSpan]::Zero)
+Register-ScheduledTask -TaskName 'actions-runner' -Action $Action -Settings $Settings -User 'NT AUTHORITY\SYSTEM' -RunLevel Highest -Force | Out-Null
+Start-ScheduledTask -TaskName 'actions-runner'
+{{- else -}}
+Start-Process -FilePath (Join-Path $RunnerDir 'run.cmd') -ArgumentList '--jitconfig', (Get-Content -Raw $JITConfigPath) -WorkingDirectory $RunnerDir
+{{- end }}
+`))
+
+func dataSourceRunnerBootstrap() *schema.Resource {
+	return &schema.Resource{
+		Description: "Renders a cloud-init configuration or PowerShell script that installs the runner application and starts a runner from its JIT configuration, ready for Azure VM `custom_data`.",
+		ReadContext: dataSourceRunnerBootstrapRead,
+		Schema: map[string]*schema.Schema{
+			"encoded_jit_config": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "Encoded JIT configuration of the runner, e.g. from a `self_hosted_runner` resource",
+			},
+			"os": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringInSlice([]string{"linux", "win"}, false),
+				Description:  "Operating system of the machine: `linux` renders cloud-init YAML, `win` renders a PowerShell script",
+			},
+			"architecture": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "x64",
+				ValidateFunc: validation.StringInSlice([]string{"x64", "arm64", "arm"}, false),
+				Description:  "Architecture of the machine, used to pick the runner application: `x64`, `arm64` or `arm`",
+			},
+			"install_dir": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Directory to install the runner in. Defaults to `/opt/actions-runner` on Linux and `C:\\actions-runner` on Windows",
+			},
+			"user": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     "runner",
+				Description: "Linux user the runner runs as, created if missing. Windows runners run as SYSTEM",
+			},
+			"run_as_service": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether to run the runner as a systemd service on Linux or a one-time scheduled task running as SYSTEM on Windows, instead of a background process",
+			},
+			"packages": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Additional packages to install through cloud-init. Linux only",
+			},
+			"pre_job_script": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Script run before every job through the `ACTIONS_RUNNER_HOOK_JOB_STARTED` hook. A shell script on Linux, a PowerShell script on Windows",
+			},
+			"download_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Download URL of the runner application used",
+			},
+			"rendered": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Rendered cloud-init configuration or PowerShell script",
+			},
+			"rendered_base64": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Base64 encoded `rendered`, ready for Azure VM `custom_data`",
+			},
+		},
+	}
+}
+
+func dataSourceRunnerBootstrapRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	osName := d.Get("os").(string)
+	architecture := d.Get("architecture").(string)
+
+	var applications []RunnerApplication
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/downloads", client.organization), &applications)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var application *RunnerApplication
+	for _, app := range applications {
+		if app.OS == osName && app.Architecture == architecture {
+			application = &app
+			break
+		}
+	}
+	if application == nil {
+		return diag.Errorf("no runner application available for %s/%s", osName, architecture)
+	}
+
+	bootstrap := runnerBootstrap{
+		EncodedJITConfig: d.Get("encoded_jit_config").(string),
+		DownloadURL:      application.DownloadURL,
+		Filename:         application.Filename,
+		SHA256Checksum:   application.SHA256Checksum,
+		InstallDir:       d.Get("install_dir").(string),
+		User:             d.Get("user").(string),
+		RunAsService:     d.Get("run_as_service").(bool),
+		Packages:         expandStringList(d.Get("packages").([]interface{})),
+		PreJobScript:     d.Get("pre_job_script").(string),
+	}
+
+	var rendered string
+	if osName == "win" {
+		if len(bootstrap.Packages) > 0 {
+			return diag.Errorf("packages is only supported on Linux")
+		}
+		rendered, err = renderWindowsBootstrap(bootstrap)
+	} else {
+		rendered, err = renderLinuxBootstrap(bootstrap)
+	}
+	if err != nil {
+		return diag.Errorf("failed to render runner bootstrap: %v", err)
+	}
+
+	checksum := sha256.Sum256([]byte(rendered))
+	d.SetId(hex.EncodeToString(checksum[:]))
+	d.Set("download_url", application.DownloadURL)
+	d.Set("rendered", rendered)
+	d.Set("rendered_base64", base64.StdEncoding.EncodeToString([]byte(rendered)))
+
+	return nil
+}
+
+func renderLinuxBootstrap(bootstrap runnerBootstrap) (string, error) {
+	if bootstrap.InstallDir == "" {
+		bootstrap.InstallDir = "/opt/actions-runner"
+	}
+
+	var script, unit, config strings.Builder
+	if err := linuxBootstrapScript.Execute(&script, bootstrap); err != nil {
+		return "", err
+	}
+	if err := linuxRunnerUnit.Execute(&unit, bootstrap); err != nil {
+		return "", err
+	}
+
+	err := linuxCloudConfig.Execute(&config, map[string]interface{}{
+		"Bootstrap": bootstrap,
+		"Script":    script.String(),
+		"Unit":      unit.String(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return config.String(), nil
+}
+
+func renderWindowsBootstrap(bootstrap runnerBootstrap) (string, error) {
+	if bootstrap.InstallDir == "" {
+		bootstrap.InstallDir = `C:\actions-runner`
+	}
+
+	var script strings.Builder
+	if err := windowsBootstrapScript.Execute(&script, bootstrap); err != nil {
+		return "", err
+	}
+
+	return script.String(), nil
+}
+
+// shellQuote quotes s as a single POSIX shell word
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// powerShellQuote quotes s as a PowerShell verbatim string
+func powerShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// yamlQuote quotes s as a YAML double-quoted scalar, which JSON strings are
+func yamlQuote(s string) (string, error) {
+	quoted, err := json.Marshal(s)
+	return string(quoted), err
+}
@@ -3,9 +3,14 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
@@ -17,8 +22,14 @@ func resourceRunnerGroup() *schema.Resource {
 		ReadContext:   resourceRunnerGroupRead,
 		UpdateContext: resourceRunnerGroupUpdate,
 		DeleteContext: resourceRunnerGroupDelete,
+		CustomizeDiff: customdiff.All(
+			resourceRunnerGroupCustomizeDiff,
+			resourceRunnerGroupSelectedWorkflowsCustomizeDiff,
+			resourceRunnerGroupSelectorCustomizeDiff,
+			resourceRunnerGroupOnDestroyCustomizeDiff,
+		),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: importStatePassthroughOrName("Runner group", lookupRunnerGroupIDs),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -36,9 +47,18 @@ func resourceRunnerGroup() *schema.Resource {
 			"selected_repository_ids": {
 				Type:        schema.TypeList,
 				Optional:    true,
+				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeInt},
 				Description: "List of repository IDs that can access the runner group",
 			},
+			"repository_selector": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MaxItems:      1,
+				ConflictsWith: []string{"selected_repository_ids"},
+				Description:   "Selects the repositories that can access the runner group by their attributes. It is evaluated against the organization's repositories on every plan and populates `selected_repository_ids`",
+				Elem:          repositorySelectorResource(),
+			},
 			"runners": {
 				Type:        schema.TypeList,
 				Optional:    true,
@@ -58,16 +78,42 @@ func resourceRunnerGroup() *schema.Resource {
 				Description: "Whether the runner group is restricted to specific workflows",
 			},
 			"selected_workflows": {
-				Type:        schema.TypeList,
-				Optional:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "List of workflows that can use the runner group",
+				Type:          schema.TypeList,
+				Optional:      true,
+				Computed:      true,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"selected_workflow"},
+				Description:   "List of workflows that can use the runner group",
+			},
+			"selected_workflow": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				ConflictsWith: []string{"selected_workflows"},
+				Description:   "Structured workflow references that can use the runner group. Each block is rendered into `selected_workflows` and checked against the repository contents at plan time",
+				Elem:          selectedWorkflowResource(),
 			},
 			"network_configuration_id": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Description: "The identifier of a hosted compute network configuration",
 			},
+			"rollback_on_failure": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to restore the previous settings when a later step of an update fails. When false, steps that succeeded are kept and recorded in state",
+			},
+			"on_destroy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"fail", "move_to", "delete_runners"}, false),
+				Description:  "What to do with runners still in the group when it is destroyed: `fail` refuses to delete the group, `move_to` moves them to `move_to_runner_group` and `delete_runners` deletes offline runners. When unset, GitHub moves remaining runners to the Default group",
+			},
+			"move_to_runner_group": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Name of the runner group that receives the remaining runners when `on_destroy` is `move_to`",
+			},
 			"default": {
 				Type:        schema.TypeBool,
 				Computed:    true,
@@ -191,11 +237,8 @@ func resourceRunnerGroupCreate(ctx context.Context, d *schema.ResourceData, m in
 	selectedRepositoryIDs := expandIntList(d.Get("selected_repository_ids").([]interface{}))
 
 	// Validate visibility and selected_repository_ids
-	if visibility == "all" && len(selectedRepositoryIDs) > 0 {
-		return diag.Errorf("selected_repository_ids cannot be set when visibility is 'all'")
-	}
-	if visibility == "selected" && len(selectedRepositoryIDs) == 0 {
-		return diag.Errorf("selected_repository_ids cannot be empty when visibility is 'selected'")
+	if err := validateRunnerGroupRepositories(visibility, selectedRepositoryIDs); err != nil {
+		return diag.FromErr(err)
 	}
 
 	req := &CreateRunnerGroupRequest{
@@ -242,9 +285,95 @@ func resourceRunnerGroupRead(ctx context.Context, d *schema.ResourceData, m inte
 	d.Set("hosted_runners_url", runnerGroup.HostedRunnersURL)
 	d.Set("workflow_restrictions_read_only", runnerGroup.WorkflowRestrictionsReadOnly)
 
+	// Only track the structured form when it is in use, so configurations
+	// using the raw selected_workflows list do not see a conflicting diff
+	if len(d.Get("selected_workflow").([]interface{})) > 0 {
+		d.Set("selected_workflow", flattenSelectedWorkflows(runnerGroup.SelectedWorkflows))
+	}
+
+	// Repositories chosen by a selector are compared against the live membership
+	if len(d.Get("repository_selector").([]interface{})) > 0 && runnerGroup.Visibility == "selected" {
+		repositories, err := listRunnerGroupRepositories(ctx, client, runnerGroupID)
+		if err != nil {
+			return diag.Errorf("failed to get runner group repositories: %v", err)
+		}
+		repositoryIDs := make([]int, len(repositories))
+		for i, repo := range repositories {
+			repositoryIDs[i] = repo.ID
+		}
+		sort.Ints(repositoryIDs)
+		d.Set("selected_repository_ids", repositoryIDs)
+	}
+
 	return nil
 }
 
+func resourceRunnerGroupCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	client := m.(*Client)
+
+	workflows := d.Get("selected_workflow").([]interface{})
+	if len(workflows) > 0 && d.HasChange("selected_workflow") {
+		rendered := make([]string, 0, len(workflows))
+		known := true
+		for _, w := range workflows {
+			workflow := expandSelectedWorkflow(w.(map[string]interface{}))
+			if workflow.Repository == "" || workflow.Path == "" || workflow.Ref == "" {
+				// Values are not known until apply
+				known = false
+				continue
+			}
+			s, err := workflow.String()
+			if err != nil {
+				return err
+			}
+			rendered = append(rendered, s)
+		}
+
+		if known {
+			if err := d.SetNew("selected_workflows", rendered); err != nil {
+				return err
+			}
+			if err := checkSelectedWorkflowsExist(ctx, client, workflows); err != nil {
+				return err
+			}
+		} else if err := d.SetNewComputed("selected_workflows"); err != nil {
+			return err
+		}
+	}
+
+	if d.NewValueKnown("restricted_to_workflows") && !d.Get("restricted_to_workflows").(bool) {
+		configured := d.GetRawConfig().GetAttr("selected_workflows")
+		if len(workflows) > 0 || (configured.IsKnown() && !configured.IsNull() && configured.LengthInt() > 0) {
+			return fmt.Errorf("selected workflows cannot be set when restricted_to_workflows is false")
+		}
+	}
+
+	return nil
+}
+
+// resourceRunnerGroupSelectedWorkflowsCustomizeDiff plans an empty selected_workflows list when
+// neither selected_workflows nor selected_workflow blocks are configured. The attribute is
+// Computed so the blocks can render into it with SetNew, which would otherwise keep the last
+// list on the group forever.
+func resourceRunnerGroupSelectedWorkflowsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+
+	config := d.GetRawConfig()
+	for _, k := range []string{"selected_workflows", "selected_workflow"} {
+		configured := config.GetAttr(k)
+		if !configured.IsKnown() || (!configured.IsNull() && configured.LengthInt() > 0) {
+			return nil
+		}
+	}
+
+	if old, _ := d.GetChange("selected_workflows"); len(old.([]interface{})) == 0 {
+		return nil
+	}
+	return d.SetNew("selected_workflows", []string{})
+}
+
 func resourceRunnerGroupUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
@@ -253,72 +382,241 @@ func resourceRunnerGroupUpdate(ctx context.Context, d *schema.ResourceData, m in
 	selectedRepositoryIDs := expandIntList(d.Get("selected_repository_ids").([]interface{}))
 
 	// Validate visibility and selected_repository_ids
-	if visibility == "all" && len(selectedRepositoryIDs) > 0 {
-		return diag.Errorf("selected_repository_ids cannot be set when visibility is 'all'")
-	}
-	if visibility == "selected" && len(selectedRepositoryIDs) == 0 {
-		return diag.Errorf("selected_repository_ids cannot be empty when visibility is 'selected'")
+	if err := validateRunnerGroupRepositories(visibility, selectedRepositoryIDs); err != nil {
+		return diag.FromErr(err)
 	}
 
-	networkConfigID := d.Get("network_configuration_id").(string)
-	req := &UpdateRunnerGroupRequest{
-		Name:                     d.Get("name").(string),
-		Visibility:               visibility,
-		AllowsPublicRepositories: boolPtr(d.Get("allows_public_repositories").(bool)),
-		RestrictedToWorkflows:    boolPtr(d.Get("restricted_to_workflows").(bool)),
-		SelectedWorkflows:        expandStringList(d.Get("selected_workflows").([]interface{})),
-		NetworkConfigurationID:   &networkConfigID,
+	oldValue := func(key string) interface{} {
+		o, _ := d.GetChange(key)
+		return o
 	}
 
-	var result RunnerGroup
-	err := client.Patch(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s", client.organization, runnerGroupID), req, &result)
-	if err != nil {
-		return diag.FromErr(err)
+	steps := []runnerGroupUpdateStep{
+		{
+			name:  "settings",
+			attrs: []string{"name", "visibility", "allows_public_repositories", "restricted_to_workflows", "selected_workflows", "selected_workflow", "network_configuration_id"},
+			apply: func() error {
+				return updateRunnerGroup(ctx, client, runnerGroupID, expandUpdateRunnerGroupRequest(d.Get))
+			},
+			rollback: func() error {
+				return updateRunnerGroup(ctx, client, runnerGroupID, expandUpdateRunnerGroupRequest(oldValue))
+			},
+		},
 	}
 
 	// Update repositories if changed
 	if d.HasChange("selected_repository_ids") {
-		_, newRepos := d.GetChange("selected_repository_ids")
-		newRepoList := expandIntList(newRepos.([]interface{}))
-
-		// Set repositories (replaces the entire list)
-		setReq := &SetRepositoriesForRunnerGroupRequest{
-			SelectedRepositoryIDs: newRepoList,
-		}
-		err := client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/repositories", client.organization, runnerGroupID), setReq, nil)
-		if err != nil {
-			return diag.Errorf("failed to update runner group repositories: %v", err)
-		}
+		steps = append(steps, runnerGroupUpdateStep{
+			name:  "repositories",
+			attrs: []string{"selected_repository_ids"},
+			apply: func() error {
+				return setRunnerGroupRepositories(ctx, client, runnerGroupID, selectedRepositoryIDs)
+			},
+			rollback: func() error {
+				return setRunnerGroupRepositories(ctx, client, runnerGroupID, expandIntList(oldValue("selected_repository_ids").([]interface{})))
+			},
+		})
 	}
 
 	// Update runners if changed
 	if d.HasChange("runners") {
-		_, newRunners := d.GetChange("runners")
-		newRunnerList := expandIntList(newRunners.([]interface{}))
+		steps = append(steps, runnerGroupUpdateStep{
+			name:  "runners",
+			attrs: []string{"runners"},
+			apply: func() error {
+				return setRunnerGroupRunners(ctx, client, runnerGroupID, expandIntList(d.Get("runners").([]interface{})))
+			},
+			rollback: func() error {
+				return setRunnerGroupRunners(ctx, client, runnerGroupID, expandIntList(oldValue("runners").([]interface{})))
+			},
+		})
+	}
 
-		// Set runners (replaces the entire list)
-		setReq := &SetRunnersForRunnerGroupRequest{
-			Runners: newRunnerList,
+	for i, step := range steps {
+		err := step.apply()
+		if err == nil {
+			continue
 		}
-		err := client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/runners", client.organization, runnerGroupID), setReq, nil)
-		if err != nil {
-			return diag.Errorf("failed to update runner group runners: %v", err)
+
+		// Steps that did not run keep their previous values in state
+		for _, pending := range steps[i:] {
+			for _, attr := range pending.attrs {
+				d.Set(attr, oldValue(attr))
+			}
+		}
+
+		applied := make([]string, 0, i)
+		for _, done := range steps[:i] {
+			applied = append(applied, done.name)
+		}
+
+		detail := fmt.Sprintf("The %s step failed: %v", step.name, err)
+		if len(applied) == 0 {
+			detail += "\n\nNo changes were applied."
+		} else if !d.Get("rollback_on_failure").(bool) {
+			detail += fmt.Sprintf("\n\nThe %s step(s) were applied and are recorded in state.", strings.Join(applied, ", "))
+		} else {
+			detail += rollbackRunnerGroupUpdate(d, steps[:i], oldValue)
 		}
+
+		return diag.Diagnostics{{
+			Severity: diag.Error,
+			Summary:  fmt.Sprintf("failed to update runner group %s", step.name),
+			Detail:   detail,
+		}}
 	}
 
 	return resourceRunnerGroupRead(ctx, d, m)
 }
 
+// runnerGroupUpdateStep is one API call of a runner group update, the attributes
+// it applies and how to undo it
+type runnerGroupUpdateStep struct {
+	name     string
+	attrs    []string
+	apply    func() error
+	rollback func() error
+}
+
+// rollbackRunnerGroupUpdate undoes applied steps in reverse order, restoring the
+// previous values in state for each step that was rolled back, and describes the outcome
+func rollbackRunnerGroupUpdate(d *schema.ResourceData, applied []runnerGroupUpdateStep, oldValue func(string) interface{}) string {
+	var rolledBack, failed []string
+	for i := len(applied) - 1; i >= 0; i-- {
+		step := applied[i]
+		if err := step.rollback(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", step.name, err))
+			continue
+		}
+		for _, attr := range step.attrs {
+			d.Set(attr, oldValue(attr))
+		}
+		rolledBack = append(rolledBack, step.name)
+	}
+
+	var detail string
+	if len(rolledBack) > 0 {
+		detail += fmt.Sprintf("\n\nRolled back the %s step(s) to the previous settings.", strings.Join(rolledBack, ", "))
+	}
+	if len(failed) > 0 {
+		detail += fmt.Sprintf("\n\nRollback failed for: %s. These changes remain applied and are recorded in state.", strings.Join(failed, "; "))
+	}
+	return detail
+}
+
+func expandUpdateRunnerGroupRequest(get func(string) interface{}) *UpdateRunnerGroupRequest {
+	networkConfigID := get("network_configuration_id").(string)
+	return &UpdateRunnerGroupRequest{
+		Name:                     get("name").(string),
+		Visibility:               get("visibility").(string),
+		AllowsPublicRepositories: boolPtr(get("allows_public_repositories").(bool)),
+		RestrictedToWorkflows:    boolPtr(get("restricted_to_workflows").(bool)),
+		SelectedWorkflows:        expandStringList(get("selected_workflows").([]interface{})),
+		NetworkConfigurationID:   &networkConfigID,
+	}
+}
+
 func resourceRunnerGroupDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	client := m.(*Client)
 
 	runnerGroupID := d.Id()
+
+	// GitHub moves runners left in a deleted group back to Default, which may
+	// expose them to every repository, so deal with them first
+	diags := evacuateRunnerGroup(ctx, client, d)
+	if diags.HasError() {
+		return diags
+	}
+
 	err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s", client.organization, runnerGroupID), nil)
 	if err != nil {
-		return diag.FromErr(err)
+		return append(diags, diag.FromErr(err)...)
 	}
 
 	d.SetId("")
+	return diags
+}
+
+// evacuateRunnerGroup applies the on_destroy behavior to the runners still in the group
+func evacuateRunnerGroup(ctx context.Context, client *Client, d *schema.ResourceData) diag.Diagnostics {
+	onDestroy := d.Get("on_destroy").(string)
+	if onDestroy == "" {
+		return nil
+	}
+
+	runnerGroupID := d.Id()
+	groupName := d.Get("name").(string)
+	runners, err := listRunnerGroupRunners(ctx, client, runnerGroupID)
+	if err != nil {
+		return diag.Errorf("failed to list runners in runner group '%s': %v", groupName, err)
+	}
+	if len(runners) == 0 {
+		return nil
+	}
+
+	var diags diag.Diagnostics
+	switch onDestroy {
+	case "fail":
+		return diag.Errorf("cannot delete runner group '%s': it still contains %d runner(s): %s", groupName, len(runners), runnerNames(runners))
+
+	case "move_to":
+		targetName := d.Get("move_to_runner_group").(string)
+		target, err := findRunnerGroupByName(ctx, client, targetName)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+
+		for _, runner := range runners {
+			err := client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%d/runners/%d", client.organization, target.ID, runner.ID), nil, nil)
+			if err != nil {
+				return append(diags, diag.Errorf("failed to move runner '%s' (%d) to runner group '%s': %v", runner.Name, runner.ID, targetName, err)...)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Moved runner '%s' to runner group '%s'", runner.Name, targetName),
+				Detail:   fmt.Sprintf("Runner %d was moved from runner group '%s' (%s) to '%s' (%d) before the group was deleted.", runner.ID, groupName, runnerGroupID, targetName, target.ID),
+			})
+		}
+
+	case "delete_runners":
+		var remaining []SelfHostedRunner
+		for _, runner := range runners {
+			if runner.Status != "offline" {
+				remaining = append(remaining, runner)
+				continue
+			}
+			err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%d", client.organization, runner.ID), nil)
+			if err != nil {
+				return append(diags, diag.Errorf("failed to delete runner '%s' (%d): %v", runner.Name, runner.ID, err)...)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  fmt.Sprintf("Deleted offline runner '%s'", runner.Name),
+				Detail:   fmt.Sprintf("Runner %d was deleted from runner group '%s' (%s) before the group was deleted.", runner.ID, groupName, runnerGroupID),
+			})
+		}
+		if len(remaining) > 0 {
+			return append(diags, diag.Errorf("cannot delete runner group '%s': %d runner(s) are not offline and were not deleted: %s", groupName, len(remaining), runnerNames(remaining))...)
+		}
+	}
+
+	return diags
+}
+
+func resourceRunnerGroupOnDestroyCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	onDestroy := d.Get("on_destroy").(string)
+	target := d.Get("move_to_runner_group").(string)
+
+	if onDestroy == "move_to" && target == "" && d.NewValueKnown("move_to_runner_group") {
+		return fmt.Errorf("move_to_runner_group must be set when on_destroy is 'move_to'")
+	}
+	if onDestroy != "move_to" && target != "" {
+		return fmt.Errorf("move_to_runner_group can only be set when on_destroy is 'move_to'")
+	}
+	if target != "" && target == d.Get("name").(string) {
+		return fmt.Errorf("move_to_runner_group cannot be the runner group being destroyed")
+	}
+
 	return nil
 }
 
@@ -363,6 +661,303 @@ func dataSourceRunnerGroupRead(ctx context.Context, d *schema.ResourceData, m in
 	return nil
 }
 
+func validateRunnerGroupRepositories(visibility string, selectedRepositoryIDs []int) error {
+	if visibility == "all" && len(selectedRepositoryIDs) > 0 {
+		return fmt.Errorf("selected_repository_ids cannot be set when visibility is 'all'")
+	}
+	if visibility == "selected" && len(selectedRepositoryIDs) == 0 {
+		return fmt.Errorf("selected_repository_ids cannot be empty when visibility is 'selected'")
+	}
+	return nil
+}
+
+// listRunnerGroups returns every runner group in the organization
+func listRunnerGroups(ctx context.Context, client *Client) ([]RunnerGroup, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/actions/runner-groups", client.organization), func(l *RunnerGroupList) (int, []RunnerGroup) {
+		return l.TotalCount, l.RunnerGroups
+	})
+}
+
+// listRunnerGroupRepositories returns every repository with access to a runner group
+func listRunnerGroupRepositories(ctx context.Context, client *Client, runnerGroupID string) ([]Repository, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/repositories", client.organization, runnerGroupID), func(l *RepositoryList) (int, []Repository) {
+		return l.TotalCount, l.Repositories
+	})
+}
+
+// listRunnerGroupRunners returns every self-hosted runner in a runner group
+func listRunnerGroupRunners(ctx context.Context, client *Client, runnerGroupID string) ([]SelfHostedRunner, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/runners", client.organization, runnerGroupID), func(l *SelfHostedRunnerList) (int, []SelfHostedRunner) {
+		return l.TotalCount, l.Runners
+	})
+}
+
+// setRunnerGroupRunners replaces the entire list of runners in a runner group
+func setRunnerGroupRunners(ctx context.Context, client *Client, runnerGroupID string, runnerIDs []int) error {
+	setReq := &SetRunnersForRunnerGroupRequest{
+		Runners: runnerIDs,
+	}
+	return client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/runners", client.organization, runnerGroupID), setReq, nil)
+}
+
+// findRunnerGroupForRunner returns the runner group that contains the runner, or nil if no
+// group does. GitHub does not report a runner's group, so the group expectedGroupID is checked
+// first, and every group's runners are scanned only when the runner is not in it.
+func findRunnerGroupForRunner(ctx context.Context, client *Client, runnerID, expectedGroupID int) (*RunnerGroup, error) {
+	if expectedGroupID > 0 {
+		runners, err := listRunnerGroupRunners(ctx, client, strconv.Itoa(expectedGroupID))
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		for _, runner := range runners {
+			if runner.ID == runnerID {
+				var runnerGroup RunnerGroup
+				err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%d", client.organization, expectedGroupID), &runnerGroup)
+				if err != nil {
+					return nil, err
+				}
+				return &runnerGroup, nil
+			}
+		}
+	}
+
+	runnerGroups, err := listRunnerGroups(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, rg := range runnerGroups {
+		if rg.ID == expectedGroupID {
+			continue
+		}
+		runners, err := listRunnerGroupRunners(ctx, client, strconv.Itoa(rg.ID))
+		if err != nil {
+			return nil, err
+		}
+		for _, runner := range runners {
+			if runner.ID == runnerID {
+				return &rg, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// lookupRunnerGroupIDs returns the IDs of the runner groups with the given name
+func lookupRunnerGroupIDs(ctx context.Context, client *Client, name string) ([]string, error) {
+	runnerGroups, err := listRunnerGroups(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, 1)
+	for _, rg := range runnerGroups {
+		if rg.Name == name {
+			ids = append(ids, strconv.Itoa(rg.ID))
+		}
+	}
+	return ids, nil
+}
+
+func findRunnerGroupByName(ctx context.Context, client *Client, name string) (*RunnerGroup, error) {
+	runnerGroups, err := listRunnerGroups(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, rg := range runnerGroups {
+		if rg.Name == name {
+			return &rg, nil
+		}
+	}
+	return nil, fmt.Errorf("Runner group with name '%s' not found", name)
+}
+
+func runnerNames(runners []SelfHostedRunner) string {
+	names := make([]string, len(runners))
+	for i, runner := range runners {
+		names[i] = fmt.Sprintf("%s (%d)", runner.Name, runner.ID)
+	}
+	return strings.Join(names, ", ")
+}
+
+func flattenRunnerGroup(rg RunnerGroup) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                              rg.ID,
+		"name":                            rg.Name,
+		"visibility":                      rg.Visibility,
+		"default":                         rg.Default,
+		"inherited":                       rg.Inherited,
+		"allows_public_repositories":      rg.AllowsPublicRepositories,
+		"restricted_to_workflows":         rg.RestrictedToWorkflows,
+		"selected_workflows":              rg.SelectedWorkflows,
+		"network_configuration_id":        rg.NetworkConfigurationID,
+		"selected_repositories_url":       rg.SelectedRepositoriesURL,
+		"runners_url":                     rg.RunnersURL,
+		"hosted_runners_url":              rg.HostedRunnersURL,
+		"workflow_restrictions_read_only": rg.WorkflowRestrictionsReadOnly,
+	}
+}
+
+// updateRunnerGroup applies settings to a runner group through the PATCH endpoint
+func updateRunnerGroup(ctx context.Context, client *Client, runnerGroupID string, req *UpdateRunnerGroupRequest) error {
+	var result RunnerGroup
+	return client.Patch(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s", client.organization, runnerGroupID), req, &result)
+}
+
+// setRunnerGroupRepositories replaces the entire list of repositories with access to a runner group
+func setRunnerGroupRepositories(ctx context.Context, client *Client, runnerGroupID string, repositoryIDs []int) error {
+	setReq := &SetRepositoriesForRunnerGroupRequest{
+		SelectedRepositoryIDs: repositoryIDs,
+	}
+	return client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%s/repositories", client.organization, runnerGroupID), setReq, nil)
+}
+
+// SelectedWorkflow is a structured reference to a workflow allowed to use a runner group
+type SelectedWorkflow struct {
+	Repository string
+	Path       string
+	Ref        string
+	RefType    string
+}
+
+var commitSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// String renders the workflow in the owner/repo/path@ref form expected by the API
+func (w SelectedWorkflow) String() (string, error) {
+	if strings.HasPrefix(w.Ref, "refs/") {
+		return "", fmt.Errorf("selected_workflow %s: ref must be a short branch name, tag name or commit SHA, got %q", w.Path, w.Ref)
+	}
+
+	switch w.RefType {
+	case "branch":
+		return fmt.Sprintf("%s/%s@refs/heads/%s", w.Repository, w.Path, w.Ref), nil
+	case "tag":
+		return fmt.Sprintf("%s/%s@refs/tags/%s", w.Repository, w.Path, w.Ref), nil
+	case "sha":
+		if !commitSHAPattern.MatchString(w.Ref) {
+			return "", fmt.Errorf("selected_workflow %s: ref %q is not a full 40 character commit SHA", w.Path, w.Ref)
+		}
+		return fmt.Sprintf("%s/%s@%s", w.Repository, w.Path, w.Ref), nil
+	}
+
+	return "", fmt.Errorf("selected_workflow %s: unsupported ref_type %q", w.Path, w.RefType)
+}
+
+func selectedWorkflowResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"repository": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^/\s]+/[^/\s]+$`), "must be a full repository name such as my-org/my-repo"),
+				Description:  "Full name of the repository containing the workflow, e.g. `my-org/my-repo`",
+			},
+			"path": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^\.github/workflows/[^/]+\.ya?ml$`), "must be a workflow file in .github/workflows"),
+				Description:  "Path of the workflow file, e.g. `.github/workflows/deploy.yaml`",
+			},
+			"ref": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotWhiteSpace,
+				Description:  "Branch name, tag name or full commit SHA the workflow must run from",
+			},
+			"ref_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "branch",
+				ValidateFunc: validation.StringInSlice([]string{"branch", "tag", "sha"}, false),
+				Description:  "Kind of ref: `branch`, `tag` or `sha`",
+			},
+		},
+	}
+}
+
+func expandSelectedWorkflow(raw map[string]interface{}) SelectedWorkflow {
+	return SelectedWorkflow{
+		Repository: raw["repository"].(string),
+		Path:       raw["path"].(string),
+		Ref:        raw["ref"].(string),
+		RefType:    raw["ref_type"].(string),
+	}
+}
+
+// parseSelectedWorkflow converts an API workflow string back into its structured form
+func parseSelectedWorkflow(s string) (SelectedWorkflow, bool) {
+	at := strings.LastIndex(s, "@")
+	if at < 0 {
+		return SelectedWorkflow{}, false
+	}
+	parts := strings.SplitN(s[:at], "/", 3)
+	if len(parts) != 3 {
+		return SelectedWorkflow{}, false
+	}
+
+	w := SelectedWorkflow{
+		Repository: parts[0] + "/" + parts[1],
+		Path:       parts[2],
+		Ref:        s[at+1:],
+		RefType:    "branch",
+	}
+	switch {
+	case strings.HasPrefix(w.Ref, "refs/heads/"):
+		w.Ref = strings.TrimPrefix(w.Ref, "refs/heads/")
+	case strings.HasPrefix(w.Ref, "refs/tags/"):
+		w.Ref, w.RefType = strings.TrimPrefix(w.Ref, "refs/tags/"), "tag"
+	case commitSHAPattern.MatchString(w.Ref):
+		w.RefType = "sha"
+	}
+	return w, true
+}
+
+func flattenSelectedWorkflows(workflows []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(workflows))
+	for _, s := range workflows {
+		w, ok := parseSelectedWorkflow(s)
+		if !ok {
+			continue
+		}
+		result = append(result, map[string]interface{}{
+			"repository": w.Repository,
+			"path":       w.Path,
+			"ref":        w.Ref,
+			"ref_type":   w.RefType,
+		})
+	}
+	return result
+}
+
+// checkSelectedWorkflowsExist verifies each repository and workflow file through
+// the contents API, since a typo would otherwise silently block every job
+func checkSelectedWorkflowsExist(ctx context.Context, client *Client, workflows []interface{}) error {
+	checkedRepos := make(map[string]bool)
+	for _, raw := range workflows {
+		w := expandSelectedWorkflow(raw.(map[string]interface{}))
+
+		if !checkedRepos[w.Repository] {
+			var repo Repository
+			err := client.Get(ctx, fmt.Sprintf("/repos/%s", w.Repository), &repo)
+			if isNotFound(err) {
+				return fmt.Errorf("selected_workflow: repository %s not found", w.Repository)
+			}
+			if err != nil {
+				return fmt.Errorf("selected_workflow: failed to check repository %s: %v", w.Repository, err)
+			}
+			checkedRepos[w.Repository] = true
+		}
+
+		var content RepositoryContent
+		err := client.Get(ctx, fmt.Sprintf("/repos/%s/contents/%s?ref=%s", w.Repository, w.Path, url.QueryEscape(w.Ref)), &content)
+		if isNotFound(err) {
+			return fmt.Errorf("selected_workflow: workflow %s not found in %s at %s %s", w.Path, w.Repository, w.RefType, w.Ref)
+		}
+		if err != nil {
+			return fmt.Errorf("selected_workflow: failed to check workflow %s in %s: %v", w.Path, w.Repository, err)
+		}
+	}
+	return nil
+}
+
 func expandIntList(configured []interface{}) []int {
 	vs := make([]int, 0, len(configured))
 	for _, v := range configured {
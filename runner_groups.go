@@ -0,0 +1,204 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceRunnerGroups() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves all GitHub self-hosted runner groups in the organization, optionally filtered.",
+		ReadContext: dataSourceRunnerGroupsRead,
+		Schema: map[string]*schema.Schema{
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression the runner group name must match",
+			},
+			"visibility": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"all", "selected", "private"}, false),
+				Description:  "Only return runner groups with this visibility",
+			},
+			"default": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return runner groups whose `default` flag matches this value",
+			},
+			"inherited": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return runner groups whose `inherited` flag matches this value",
+			},
+			"network_configuration_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return runner groups using this hosted compute network configuration",
+			},
+			"repository": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return runner groups that this repository in the organization can use",
+			},
+			"runner_groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of matching runner groups",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"visibility": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"default": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"inherited": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"allows_public_repositories": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"restricted_to_workflows": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"selected_workflows": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"network_configuration_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"selected_repositories_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"runners_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hosted_runners_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"workflow_restrictions_read_only": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceRunnerGroupsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerGroups, err := listRunnerGroups(ctx, client)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	// Look up the repository once so each group only needs an access check
+	var repository *Repository
+	if v, ok := d.GetOk("repository"); ok {
+		repository = &Repository{}
+		err := client.Get(ctx, fmt.Sprintf("/repos/%s/%s", client.organization, v.(string)), repository)
+		if err != nil {
+			return diag.Errorf("failed to get repository '%s': %v", v.(string), err)
+		}
+	}
+
+	config := d.GetRawConfig()
+	visibility := d.Get("visibility").(string)
+	networkConfigID := d.Get("network_configuration_id").(string)
+
+	result := make([]map[string]interface{}, 0, len(runnerGroups))
+	for _, rg := range runnerGroups {
+		if nameRegex != nil && !nameRegex.MatchString(rg.Name) {
+			continue
+		}
+		if visibility != "" && rg.Visibility != visibility {
+			continue
+		}
+		if !config.GetAttr("default").IsNull() && rg.Default != d.Get("default").(bool) {
+			continue
+		}
+		if !config.GetAttr("inherited").IsNull() && rg.Inherited != d.Get("inherited").(bool) {
+			continue
+		}
+		if networkConfigID != "" && rg.NetworkConfigurationID != networkConfigID {
+			continue
+		}
+		if repository != nil {
+			allowed, err := runnerGroupAllowsRepository(ctx, client, rg, repository)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			if !allowed {
+				continue
+			}
+		}
+
+		result = append(result, flattenRunnerGroup(rg))
+	}
+
+	d.SetId("runner-groups")
+	d.Set("runner_groups", result)
+
+	return nil
+}
+
+// runnerGroupAllowsRepository reports whether workflows in repo can run on the group's runners
+func runnerGroupAllowsRepository(ctx context.Context, client *Client, rg RunnerGroup, repo *Repository) (bool, error) {
+	if !repo.Private && !rg.AllowsPublicRepositories {
+		return false, nil
+	}
+
+	switch rg.Visibility {
+	case "all":
+		return true, nil
+	case "private":
+		return repo.Private, nil
+	case "selected":
+		repositories, err := listRunnerGroupRepositories(ctx, client, strconv.Itoa(rg.ID))
+		if err != nil {
+			return false, fmt.Errorf("failed to get repositories for runner group '%s': %v", rg.Name, err)
+		}
+		for _, r := range repositories {
+			if r.ID == repo.ID {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// runnerLabelPattern matches the characters GitHub accepts in a runner label
+var runnerLabelPattern = regexp.MustCompile(`^[^\s,]+$`)
+
+// defaultRunnerLabels are the labels GitHub assigns to runners itself. They can only be
+// set as read-only labels when the runner is created.
+var defaultRunnerLabels = []string{"self-hosted", "linux", "windows", "macos", "x64", "arm", "arm64"}
+
+const maxRunnerLabelLength = 256
+
+func resourceRunnerLabels() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manages a set of custom labels on a self-hosted runner without touching labels it does not declare.",
+		CreateContext: resourceRunnerLabelsCreate,
+		ReadContext:   resourceRunnerLabelsRead,
+		UpdateContext: resourceRunnerLabelsUpdate,
+		DeleteContext: resourceRunnerLabelsDelete,
+		CustomizeDiff: resourceRunnerLabelsCustomizeDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceRunnerLabelsImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"runner_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "ID of the self-hosted runner",
+			},
+			"labels": {
+				Type:        schema.TypeSet,
+				Required:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateRunnerLabel},
+				Description: "Custom labels to add to the runner. Other labels on the runner are left alone",
+			},
+		},
+	}
+}
+
+func resourceRunnerLabelsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerID := d.Get("runner_id").(string)
+	labels := expandStringList(d.Get("labels").(*schema.Set).List())
+
+	if err := addRunnerLabels(ctx, client, runnerID, labels); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(runnerID)
+
+	return resourceRunnerLabelsRead(ctx, d, m)
+}
+
+func resourceRunnerLabelsRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	current, err := listRunnerLabels(ctx, client, d.Id())
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	// Only report the declared labels that are still present, so removed ones are added back
+	labels := make([]string, 0)
+	for _, label := range expandStringList(d.Get("labels").(*schema.Set).List()) {
+		if hasRunnerLabel(current, label) {
+			labels = append(labels, label)
+		}
+	}
+
+	d.Set("runner_id", d.Id())
+	d.Set("labels", labels)
+
+	return nil
+}
+
+func resourceRunnerLabelsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	oldLabels, newLabels := d.GetChange("labels")
+	added := expandStringList(newLabels.(*schema.Set).Difference(oldLabels.(*schema.Set)).List())
+	removed := expandStringList(oldLabels.(*schema.Set).Difference(newLabels.(*schema.Set)).List())
+
+	if err := updateRunnerLabels(ctx, client, d.Id(), added, removed); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return resourceRunnerLabelsRead(ctx, d, m)
+}
+
+func resourceRunnerLabelsDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	labels := expandStringList(d.Get("labels").(*schema.Set).List())
+	if err := updateRunnerLabels(ctx, client, d.Id(), nil, labels); err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceRunnerLabelsImport adopts every custom label the runner currently has
+func resourceRunnerLabelsImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	client := m.(*Client)
+
+	current, err := listRunnerLabels(ctx, client, d.Id())
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make([]string, 0)
+	for _, label := range current {
+		if label.Type == "custom" {
+			labels = append(labels, label.Name)
+		}
+	}
+
+	d.Set("labels", labels)
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceRunnerLabelsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("labels") {
+		return nil
+	}
+	return checkRunnerLabels(nil, expandStringList(d.Get("labels").(*schema.Set).List()))
+}
+
+func listRunnerLabels(ctx context.Context, client *Client, runnerID string) ([]RunnerLabel, error) {
+	var labelList RunnerLabelList
+	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, runnerID), &labelList)
+	if err != nil {
+		return nil, err
+	}
+	return labelList.Labels, nil
+}
+
+func addRunnerLabels(ctx context.Context, client *Client, runnerID string, labels []string) error {
+	addReq := &AddLabelsRequest{
+		Labels: labels,
+	}
+
+	err := client.Post(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, runnerID), addReq, nil)
+	if err != nil {
+		return fmt.Errorf("failed to add runner labels: %v", err)
+	}
+	return nil
+}
+
+// updateRunnerLabels adds and removes individual custom labels, leaving every other label alone
+func updateRunnerLabels(ctx context.Context, client *Client, runnerID string, added, removed []string) error {
+	for _, label := range removed {
+		err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels/%s", client.organization, runnerID, url.PathEscape(label)), nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to remove runner label %s: %v", label, err)
+		}
+	}
+
+	if len(added) > 0 {
+		return addRunnerLabels(ctx, client, runnerID, added)
+	}
+	return nil
+}
+
+// hasRunnerLabel reports whether labels contains name. Runner labels are case-insensitive.
+func hasRunnerLabel(labels []RunnerLabel, name string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateRunnerLabel checks a single label against GitHub's label rules
+func validateRunnerLabel(v interface{}, k string) ([]string, []error) {
+	label := v.(string)
+	if len(label) > maxRunnerLabelLength {
+		return nil, []error{fmt.Errorf("%s: label %q is longer than %d characters", k, label, maxRunnerLabelLength)}
+	}
+	if !runnerLabelPattern.MatchString(label) {
+		return nil, []error{fmt.Errorf("%s: label %q must not be empty or contain whitespace or commas", k, label)}
+	}
+	return nil, nil
+}
+
+// checkRunnerLabels checks readonly and custom labels together: labels differing only by case
+// are duplicates, default labels cannot be custom labels, and no label can be in both lists.
+// Empty strings are values not known until apply and are skipped.
+func checkRunnerLabels(readonlyLabels, customLabels []string) error {
+	seen := make(map[string]string)
+	for _, list := range []struct {
+		name   string
+		labels []string
+	}{
+		{"readonly_labels", readonlyLabels},
+		{"labels", customLabels},
+	} {
+		for _, label := range list.labels {
+			if label == "" {
+				continue
+			}
+			key := strings.ToLower(label)
+			if previous, ok := seen[key]; ok {
+				if previous == list.name {
+					return fmt.Errorf("label %q is declared more than once, labels are case-insensitive", label)
+				}
+				return fmt.Errorf("label %q is declared in both readonly_labels and labels", label)
+			}
+			seen[key] = list.name
+
+			if list.name == "labels" && containsString(defaultRunnerLabels, key) {
+				return fmt.Errorf("labels: %q is a default runner label and cannot be a custom label, set it in the runner's readonly_labels", label)
+			}
+		}
+	}
+	return nil
+}
@@ -1,12 +1,22 @@
 package main
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"regexp"
 	"strconv"
+	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/retry"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 )
 
 func resourceSelfHostedRunner() *schema.Resource {
@@ -16,38 +26,120 @@ func resourceSelfHostedRunner() *schema.Resource {
 		ReadContext:   resourceSelfHostedRunnerRead,
 		UpdateContext: resourceSelfHostedRunnerUpdate,
 		DeleteContext: resourceSelfHostedRunnerDelete,
+		CustomizeDiff: customdiff.All(
+			resourceSelfHostedRunnerCustomizeDiff,
+			resourceSelfHostedRunnerLabelsCustomizeDiff,
+		),
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceSelfHostedRunnerImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
-				Type:        schema.TypeString,
-				Required:    true,
-				ForceNew:    true,
-				Description: "Name of the self-hosted runner",
+				Type:             schema.TypeString,
+				Optional:         true,
+				Computed:         true,
+				ForceNew:         true,
+				ExactlyOneOf:     []string{"name", "name_prefix"},
+				DiffSuppressFunc: suppressRunnerNameSuffixDiff,
+				Description:      "Name of the self-hosted runner. Conflicts with `name_prefix`",
+			},
+			"name_prefix": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ForceNew:     true,
+				ExactlyOneOf: []string{"name", "name_prefix"},
+				ValidateFunc: validation.StringLenBetween(1, 54),
+				Description:  "Prefix of a generated runner name, `<name_prefix>-<random suffix>`. Unlike `name`, it lets a replacement runner register before the old one is deleted with `create_before_destroy`",
 			},
 			"runner_group_id": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "ID of the runner group to add the runner to",
+				Computed:    true,
+				Description: "ID of the runner group to add the runner to. Defaults to the organization's default runner group",
+			},
+			"runner_group_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the runner group the runner is in",
+			},
+			"on_name_conflict": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"fail", "replace", "suffix"}, false),
+				Description:  "What to do when a runner with the same name already exists: `fail`, `replace` the existing runner if it is offline, or append a random `suffix` to the name",
 			},
 			"readonly_labels": {
 				Type:        schema.TypeList,
 				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
-				Description: "Read-only labels to be set during runner creation (cannot be modified after creation)",
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateRunnerLabel},
+				Description: "Read-only labels to be set during runner creation. They cannot be modified afterwards, so changing them replaces the runner",
 			},
 			"labels": {
 				Type:        schema.TypeList,
 				Required:    true,
-				Elem:        &schema.Schema{Type: schema.TypeString},
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateRunnerLabel},
 				Description: "Custom labels to add to the runner",
 			},
 			"work_folder": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				Default:     "_work",
-				Description: "Working directory for job execution",
+				ForceNew:    true,
+				Description: "Working directory for job execution. It is part of the JIT configuration, so changing it replaces the runner",
+			},
+			"wait_for_online": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether create waits, up to the create timeout, until the runner reports the `online` status. Only enable it when the machine running the JIT configuration does not depend on this resource, otherwise the wait cannot succeed",
+			},
+			"authoritative": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Whether `labels` is the complete list of custom labels. When false, only the declared labels are added and removed, and labels added by other tools are left alone",
+			},
+			"keepers": {
+				Type:        schema.TypeMap,
+				Optional:    true,
+				ForceNew:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Arbitrary values that, when changed, replace the runner with a new one and a fresh JIT configuration",
+			},
+			"max_age": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validateDuration,
+				Description:  "Maximum age of the JIT configuration as a Go duration, e.g. `720h`. Once exceeded, the next plan replaces the runner",
+			},
+			"created_at": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Time the JIT configuration was generated, in RFC 3339 format",
+			},
+			"jit_config_consumed": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the ephemeral runner has been seen busy, or offline after having been online, meaning its JIT configuration has been used. The next plan then replaces the runner",
+			},
+			"jit_config_seen_online": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the runner has been seen online or busy since its JIT configuration was generated",
+			},
+			"deletion_policy": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "fail",
+				ValidateFunc: validation.StringInSlice([]string{"wait", "force", "fail"}, false),
+				Description:  "What to do when the runner is not offline on delete: `wait` until it goes offline, up to the delete timeout, `force` delete it anyway, or `fail`",
 			},
 			"os": {
 				Type:        schema.TypeString,
@@ -81,6 +173,43 @@ func resourceSelfHostedRunner() *schema.Resource {
 				Sensitive:   true,
 				Description: "Encoded JIT configuration for the runner",
 			},
+			"jit_config": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Runner settings decoded from the JIT configuration. Credentials are only available through `encoded_jit_config`",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"agent_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"agent_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"pool_id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"pool_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"server_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"github_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"work_folder": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
@@ -98,7 +227,13 @@ func dataSourceSelfHostedRunner() *schema.Resource {
 			"runner_group_id": {
 				Type:        schema.TypeInt,
 				Optional:    true,
-				Description: "ID of the runner group to search in",
+				Computed:    true,
+				Description: "ID of the runner group to search in. When not set, it is the ID of the group the runner is in",
+			},
+			"runner_group_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Name of the runner group the runner is in",
 			},
 			"os": {
 				Type:        schema.TypeString,
@@ -134,6 +269,13 @@ func resourceSelfHostedRunnerCreate(ctx context.Context, d *schema.ResourceData,
 	client := m.(*Client)
 
 	name := d.Get("name").(string)
+	if prefix := d.Get("name_prefix").(string); prefix != "" {
+		var err error
+		name, err = generateRunnerName(prefix)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
 	runnerGroupID := d.Get("runner_group_id").(int)
 	readonlyLabels := expandStringList(d.Get("readonly_labels").([]interface{}))
 	labels := expandStringList(d.Get("labels").([]interface{}))
@@ -157,26 +299,60 @@ func resourceSelfHostedRunnerCreate(ctx context.Context, d *schema.ResourceData,
 		WorkFolder:     workFolder,
 	}
 
+	var diags diag.Diagnostics
 	var result JITConfigResponse
-	err := client.Post(ctx, fmt.Sprintf("/orgs/%s/actions/runners/generate-jitconfig", client.organization), req, &result)
+	adopted, err := generateRunnerJITConfig(ctx, client, req, &result, d.Timeout(schema.TimeoutCreate))
+	if isConflict(err) {
+		diags, err = resolveRunnerNameConflict(ctx, client, d.Get("on_name_conflict").(string), req, &result)
+	}
 	if err != nil {
-		return diag.FromErr(err)
+		return append(diags, diag.FromErr(err)...)
 	}
 
 	d.SetId(strconv.Itoa(result.Runner.ID))
+	d.Set("name", result.Runner.Name)
 	d.Set("encoded_jit_config", result.EncodedJITConfig)
+	d.Set("created_at", time.Now().UTC().Format(time.RFC3339))
+	d.Set("jit_config_consumed", false)
+	d.Set("jit_config_seen_online", false)
+
+	// Setting the ID before returning an error saves the runner as tainted, so the next apply
+	// deletes it and creates a replacement instead of leaking it
+	if adopted {
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Runner was created but its JIT configuration was lost",
+			Detail: fmt.Sprintf("Creating runner %s failed after GitHub had already created it as runner %d. "+
+				"The runner has been adopted and marked as tainted, so the next apply replaces it.", result.Runner.Name, result.Runner.ID),
+		})
+	}
 
 	// Set custom labels manually after runner creation
-	setReq := &SetLabelsRequest{
-		Labels: labels,
-	}
+	if d.Get("authoritative").(bool) {
+		setReq := &SetLabelsRequest{
+			Labels: labels,
+		}
 
-	err = client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, strconv.Itoa(result.Runner.ID)), setReq, nil)
+		err = client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, strconv.Itoa(result.Runner.ID)), setReq, nil)
+	} else {
+		err = addRunnerLabels(ctx, client, d.Id(), labels)
+	}
 	if err != nil {
-		return diag.Errorf("failed to set runner labels: %v", err)
+		return append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  "Failed to set runner labels",
+			Detail: fmt.Sprintf("Runner %d was created but setting its labels failed: %v. "+
+				"The runner has been marked as tainted, so the next apply replaces it.", result.Runner.ID, err),
+		})
 	}
 
-	return resourceSelfHostedRunnerRead(ctx, d, m)
+	if d.Get("wait_for_online").(bool) {
+		if err := waitForSelfHostedRunnerOnline(ctx, client, d.Id(), d.Timeout(schema.TimeoutCreate)); err != nil {
+			return append(diags, diag.FromErr(err)...)
+		}
+	}
+
+	return append(diags, resourceSelfHostedRunnerRead(ctx, d, m)...)
 }
 
 func resourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -185,6 +361,17 @@ func resourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m
 	runnerID := d.Id()
 	var runner SelfHostedRunner
 	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s", client.organization, runnerID), &runner)
+	if isNotFound(err) {
+		// JIT runners deregister themselves after their job, which consumes the JIT
+		// configuration. Drop the runner from state so a fresh one is planned.
+		name := d.Get("name").(string)
+		d.SetId("")
+		return diag.Diagnostics{{
+			Severity: diag.Warning,
+			Summary:  "Self-hosted runner no longer exists",
+			Detail:   fmt.Sprintf("Runner %s (%s) was not found. Its JIT configuration was consumed or it was removed outside Terraform, so a new runner will be created.", name, runnerID),
+		}}
+	}
 	if err != nil {
 		return diag.FromErr(err)
 	}
@@ -195,14 +382,128 @@ func resourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m
 	d.Set("busy", runner.Busy)
 	d.Set("ephemeral", runner.Ephemeral)
 
+	// An ephemeral runner runs at most one job before it deregisters. Once it has picked up a
+	// job, or has gone offline after having been online, its JIT configuration cannot be used
+	// again, so remember that and let the next plan replace it. A runner that is merely online
+	// and idle is still waiting for its job and is left alone.
+	var diags diag.Diagnostics
+	seenOnline := d.Get("jit_config_seen_online").(bool)
+	consumed := d.Get("jit_config_consumed").(bool) ||
+		(runner.Ephemeral && (runner.Busy || (runner.Status == "offline" && seenOnline)))
+	d.Set("jit_config_seen_online", seenOnline || runner.Status == "online" || runner.Busy)
+	d.Set("jit_config_consumed", consumed)
+	if consumed {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "JIT configuration of self-hosted runner has been used",
+			Detail:   fmt.Sprintf("Ephemeral runner %s (%s) has picked up a job or gone offline after having been online, so its JIT configuration cannot be used again. The runner will be replaced; deletion_policy decides what happens while it is still running a job.", runner.Name, runnerID),
+		})
+	}
+
+	// Check the group in state first and only scan all groups when the runner is not in it,
+	// so a runner moved to another group shows up as drift
+	if groupDiags := setSelfHostedRunnerGroup(ctx, client, d, runner.ID, d.Get("runner_group_id").(int), false); groupDiags != nil {
+		return append(diags, groupDiags...)
+	}
+
+	// The API does not report when the runner was created, so start the clock
+	// for runners created or imported before created_at was tracked
+	if d.Get("created_at").(string) == "" {
+		d.Set("created_at", time.Now().UTC().Format(time.RFC3339))
+	}
+
 	// Extract all label names
 	labelNames := make([]string, len(runner.Labels))
+	readonlyLabels := make([]string, 0)
+	customLabels := make([]string, 0)
 	for i, label := range runner.Labels {
 		labelNames[i] = label.Name
+		if label.Type == "read-only" {
+			readonlyLabels = append(readonlyLabels, label.Name)
+		} else {
+			customLabels = append(customLabels, label.Name)
+		}
 	}
 	d.Set("all_labels", labelNames)
 
-	return nil
+	// readonly_labels forces replacement, so only a declared label that is gone counts as drift.
+	// GitHub may report a declared label with another type or case. Imported runners have no
+	// declared labels yet and take the read-only labels as reported.
+	declaredReadonlyLabels := expandStringList(d.Get("readonly_labels").([]interface{}))
+	if len(declaredReadonlyLabels) == 0 {
+		d.Set("readonly_labels", readonlyLabels)
+	} else {
+		present := make([]string, 0, len(declaredReadonlyLabels))
+		for _, label := range declaredReadonlyLabels {
+			if hasRunnerLabel(runner.Labels, label) {
+				present = append(present, label)
+			}
+		}
+		d.Set("readonly_labels", present)
+
+		unclaimed := make([]string, 0, len(customLabels))
+		for _, label := range customLabels {
+			if !containsFold(declaredReadonlyLabels, label) {
+				unclaimed = append(unclaimed, label)
+			}
+		}
+		customLabels = unclaimed
+	}
+
+	// Imported runners have no declared labels and take every custom label, whatever
+	// authoritative reads as before the configuration is applied
+	declaredLabels := expandStringList(d.Get("labels").([]interface{}))
+	if d.Get("authoritative").(bool) || len(declaredLabels) == 0 {
+		d.Set("labels", orderLabels(declaredLabels, customLabels))
+	} else {
+		// Labels managed by other tools are not drift, only declared labels that went missing are
+		labels := make([]string, 0)
+		for _, label := range declaredLabels {
+			if hasRunnerLabel(runner.Labels, label) {
+				labels = append(labels, label)
+			}
+		}
+		d.Set("labels", labels)
+	}
+
+	if encoded := d.Get("encoded_jit_config").(string); encoded != "" {
+		settings, err := decodeJITRunnerSettings(encoded)
+		if err != nil {
+			tflog.Warn(ctx, "Failed to decode JIT configuration", map[string]interface{}{
+				"runner_id": runnerID,
+				"error":     err.Error(),
+			})
+		} else {
+			d.Set("jit_config", []interface{}{map[string]interface{}{
+				"agent_id":    settings.AgentID,
+				"agent_name":  settings.AgentName,
+				"pool_id":     settings.PoolID,
+				"pool_name":   settings.PoolName,
+				"server_url":  settings.ServerURL,
+				"github_url":  settings.GitHubURL,
+				"work_folder": settings.WorkFolder,
+			}})
+		}
+	}
+
+	return diags
+}
+
+// resourceSelfHostedRunnerImport accepts a runner ID or name:<name> and sets the defaults of the
+// optional arguments, which the SDK only applies from configuration
+func resourceSelfHostedRunnerImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	results, err := importStatePassthroughOrName("Self-hosted runner", lookupSelfHostedRunnerIDs)(ctx, d, m)
+	if err != nil {
+		return nil, err
+	}
+
+	d.Set("authoritative", true)
+	d.Set("on_name_conflict", "fail")
+	d.Set("deletion_policy", "fail")
+	d.Set("wait_for_online", false)
+	d.Set("work_folder", "_work")
+
+	return results, nil
 }
 
 func resourceSelfHostedRunnerUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -251,37 +552,6 @@ func resourceSelfHostedRunnerUpdate(ctx context.Context, d *schema.ResourceData,
 		}
 	}
 
-	// Check if readonly_labels has changed and validate no read-only labels are being removed
-	if d.HasChange("readonly_labels") {
-		oldReadonlyLabels, newReadonlyLabels := d.GetChange("readonly_labels")
-		oldReadonlyLabelList := expandStringList(oldReadonlyLabels.([]interface{}))
-		newReadonlyLabelList := expandStringList(newReadonlyLabels.([]interface{}))
-
-		// Validate readonly_labels are not empty
-		if len(newReadonlyLabelList) == 0 {
-			d.Set("readonly_labels", oldReadonlyLabelList)
-			return diag.Errorf("readonly_labels cannot be empty")
-		}
-
-		// Check if any read-only labels from the runner are being removed
-		for _, oldLabel := range oldReadonlyLabelList {
-			if readOnlyLabelsFromRunner[oldLabel] {
-				// Check if this read-only label is still in the new list
-				found := false
-				for _, newLabel := range newReadonlyLabelList {
-					if newLabel == oldLabel {
-						found = true
-						break
-					}
-				}
-				if !found {
-					d.Set("readonly_labels", oldReadonlyLabelList)
-					return diag.Errorf("cannot remove read-only label: %s", oldLabel)
-				}
-			}
-		}
-	}
-
 	// Update labels if changed
 	if d.HasChange("labels") {
 		oldLabels, newLabels := d.GetChange("labels")
@@ -302,15 +572,37 @@ func resourceSelfHostedRunnerUpdate(ctx context.Context, d *schema.ResourceData,
 			}
 		}
 
-		// Set labels (only custom labels)
-		setReq := &SetLabelsRequest{
-			Labels: customLabels,
-		}
+		if d.Get("authoritative").(bool) {
+			// Set labels (only custom labels)
+			setReq := &SetLabelsRequest{
+				Labels: customLabels,
+			}
 
-		err = client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, runnerID), setReq, nil)
-		if err != nil {
-			d.Set("labels", oldLabelList)
-			return diag.Errorf("failed to update runner labels: %v", err)
+			err = client.Put(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s/labels", client.organization, runnerID), setReq, nil)
+			if err != nil {
+				d.Set("labels", oldLabelList)
+				return diag.Errorf("failed to update runner labels: %v", err)
+			}
+		} else {
+			// Only add and remove the labels that changed, leaving labels managed elsewhere alone
+			added := make([]string, 0)
+			for _, label := range customLabels {
+				if !containsString(oldLabelList, label) {
+					added = append(added, label)
+				}
+			}
+			removed := make([]string, 0)
+			for _, label := range oldLabelList {
+				if !containsString(newLabelList, label) && !readOnlyLabelsFromRunner[label] {
+					removed = append(removed, label)
+				}
+			}
+
+			err = updateRunnerLabels(ctx, client, runnerID, added, removed)
+			if err != nil {
+				d.Set("labels", oldLabelList)
+				return diag.Errorf("failed to update runner labels: %v", err)
+			}
 		}
 	}
 
@@ -325,23 +617,50 @@ func resourceSelfHostedRunnerDelete(ctx context.Context, d *schema.ResourceData,
 	// Get current runner to check status
 	var currentRunner SelfHostedRunner
 	err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s", client.organization, runnerID), &currentRunner)
+	if isNotFound(err) {
+		d.SetId("")
+		return nil
+	}
 	if err != nil {
 		return diag.Errorf("failed to get runner status: %v", err)
 	}
 
-	// Validate that runner is offline before deletion
+	var diags diag.Diagnostics
 	if currentRunner.Status != "offline" {
-		return diag.Errorf("cannot delete runner: runner must be offline before deletion, current status: %s", currentRunner.Status)
+		switch d.Get("deletion_policy").(string) {
+		case "wait":
+			var gone bool
+			gone, diags = waitForSelfHostedRunnerOffline(ctx, client, runnerID, d.Timeout(schema.TimeoutDelete))
+			if diags.HasError() {
+				return diags
+			}
+			if gone {
+				d.SetId("")
+				return diags
+			}
+		case "force":
+			detail := fmt.Sprintf("Runner %s (%s) was %s when it was deleted.", currentRunner.Name, runnerID, currentRunner.Status)
+			if currentRunner.Busy {
+				detail = fmt.Sprintf("Runner %s (%s) was %s and running a job when it was deleted; the job may fail.", currentRunner.Name, runnerID, currentRunner.Status)
+			}
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Warning,
+				Summary:  "Deleted a runner that was not offline",
+				Detail:   detail,
+			})
+		default:
+			return diag.Errorf("cannot delete runner: runner must be offline before deletion, current status: %s", currentRunner.Status)
+		}
 	}
 
 	// Delete the runner from GitHub
 	err = client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s", client.organization, runnerID), nil)
 	if err != nil {
-		return diag.Errorf("failed to delete runner: %v", err)
+		return append(diags, diag.Errorf("failed to delete runner: %v", err)...)
 	}
 
 	d.SetId("")
-	return nil
+	return diags
 }
 
 func dataSourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
@@ -351,19 +670,13 @@ func dataSourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData,
 	runnerGroupID := d.Get("runner_group_id").(int)
 
 	// Search for runner by name
-	var runnerList SelfHostedRunnerList
-	path := fmt.Sprintf("/orgs/%s/actions/runners", client.organization)
-	if runnerGroupID > 0 {
-		path = fmt.Sprintf("/orgs/%s/actions/runner-groups/%d/runners", client.organization, runnerGroupID)
-	}
-
-	err := client.Get(ctx, path, &runnerList)
+	runners, err := listRunners(ctx, client, runnerGroupID)
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
 	var foundRunner *SelfHostedRunner
-	for _, runner := range runnerList.Runners {
+	for _, runner := range runners {
 		if runner.Name == name {
 			foundRunner = &runner
 			break
@@ -381,6 +694,10 @@ func dataSourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData,
 	d.Set("busy", foundRunner.Busy)
 	d.Set("ephemeral", foundRunner.Ephemeral)
 
+	if diags := setSelfHostedRunnerGroup(ctx, client, d, foundRunner.ID, runnerGroupID, true); diags != nil {
+		return diags
+	}
+
 	// Extract all label names
 	labelNames := make([]string, len(foundRunner.Labels))
 	for i, label := range foundRunner.Labels {
@@ -391,6 +708,431 @@ func dataSourceSelfHostedRunnerRead(ctx context.Context, d *schema.ResourceData,
 	return nil
 }
 
+// setSelfHostedRunnerGroup sets runner_group_id and runner_group_name from the group that
+// contains the runner, checking runnerGroupID first. When the runner is known to be in
+// runnerGroupID, only that group's name is looked up.
+func setSelfHostedRunnerGroup(ctx context.Context, client *Client, d *schema.ResourceData, runnerID, runnerGroupID int, inGroup bool) diag.Diagnostics {
+	var runnerGroup *RunnerGroup
+	if inGroup && runnerGroupID > 0 {
+		runnerGroup = &RunnerGroup{}
+		err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runner-groups/%d", client.organization, runnerGroupID), runnerGroup)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	} else {
+		var err error
+		runnerGroup, err = findRunnerGroupForRunner(ctx, client, runnerID, runnerGroupID)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	if runnerGroup == nil {
+		d.Set("runner_group_id", nil)
+		d.Set("runner_group_name", "")
+		return nil
+	}
+	d.Set("runner_group_id", runnerGroup.ID)
+	d.Set("runner_group_name", runnerGroup.Name)
+	return nil
+}
+
+// generateRunnerJITConfig creates a runner, retrying transient failures until timeout. A failed
+// request may still have created the runner, so before each retry the runner is looked up by
+// name. A runner found that way is returned in result with adopted set if it matches the
+// request; its JIT configuration cannot be retrieved again. Any other runner with the name
+// is left alone.
+func generateRunnerJITConfig(ctx context.Context, client *Client, req *JITConfigRequest, result *JITConfigResponse, timeout time.Duration) (bool, error) {
+	path := fmt.Sprintf("/orgs/%s/actions/runners/generate-jitconfig", client.organization)
+
+	adopted := false
+	attempted := false
+	err := retry.RetryContext(ctx, timeout, func() *retry.RetryError {
+		if attempted {
+			existing, err := findRunnerByName(ctx, client, req.Name)
+			if err != nil {
+				return retry.RetryableError(err)
+			}
+			if existing != nil {
+				matches, err := runnerMatchesJITRequest(ctx, client, existing, req)
+				if err != nil {
+					return retry.RetryableError(err)
+				}
+				if !matches {
+					return retry.NonRetryableError(fmt.Errorf("runner name %s is used by runner %d, which does not match this runner's group and labels or has been online, so it was not adopted", req.Name, existing.ID))
+				}
+				tflog.Warn(ctx, "Adopting runner created by a failed request", map[string]interface{}{
+					"runner_id":   existing.ID,
+					"runner_name": existing.Name,
+				})
+				result.Runner = *existing
+				adopted = true
+				return nil
+			}
+		}
+		attempted = true
+
+		err := client.Post(ctx, path, req, result)
+		if isTransient(err) {
+			tflog.Warn(ctx, "Creating runner failed, retrying", map[string]interface{}{
+				"runner_name": req.Name,
+				"error":       err.Error(),
+			})
+			return retry.RetryableError(err)
+		}
+		if err != nil {
+			return retry.NonRetryableError(err)
+		}
+		return nil
+	})
+	return adopted, err
+}
+
+// runnerMatchesJITRequest reports whether runner looks like the one req would have created:
+// offline, with exactly the requested read-only labels and in the requested runner group
+func runnerMatchesJITRequest(ctx context.Context, client *Client, runner *SelfHostedRunner, req *JITConfigRequest) (bool, error) {
+	if runner.Status != "offline" || runner.Busy || len(runner.Labels) != len(req.ReadOnlyLabels) {
+		return false, nil
+	}
+	for _, label := range req.ReadOnlyLabels {
+		if !hasRunnerLabel(runner.Labels, label) {
+			return false, nil
+		}
+	}
+
+	runnerGroup, err := findRunnerGroupForRunner(ctx, client, runner.ID, req.RunnerGroupID)
+	if err != nil {
+		return false, err
+	}
+	if runnerGroup == nil {
+		return false, nil
+	}
+	if req.RunnerGroupID > 0 {
+		return runnerGroup.ID == req.RunnerGroupID, nil
+	}
+	return runnerGroup.Default, nil
+}
+
+// resolveRunnerNameConflict handles a generate-jitconfig conflict with an existing runner of the
+// same name according to policy, retrying the request when the conflict could be resolved. The
+// returned warnings name the conflicting runner.
+func resolveRunnerNameConflict(ctx context.Context, client *Client, policy string, req *JITConfigRequest, result *JITConfigResponse) (diag.Diagnostics, error) {
+	existing, err := findRunnerByName(ctx, client, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, fmt.Errorf("runner name %s conflicts with an existing runner that could not be found", req.Name)
+	}
+
+	var diags diag.Diagnostics
+	switch policy {
+	case "replace":
+		if existing.Status != "offline" {
+			return nil, fmt.Errorf("runner name %s is used by runner %d, which cannot be replaced because it is %s", req.Name, existing.ID, existing.Status)
+		}
+		err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%d", client.organization, existing.ID), nil)
+		if err != nil && !isNotFound(err) {
+			return nil, fmt.Errorf("failed to delete conflicting runner %d: %v", existing.ID, err)
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Replaced existing runner with the same name",
+			Detail:   fmt.Sprintf("Runner name %s was used by offline runner %d, which has been deleted so the new runner could take the name.", req.Name, existing.ID),
+		})
+	case "suffix":
+		name, err := generateRunnerName(req.Name)
+		if err != nil {
+			return nil, err
+		}
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Warning,
+			Summary:  "Runner name is taken, using a suffixed name",
+			Detail:   fmt.Sprintf("Runner name %s is used by runner %d, so the new runner is named %s.", req.Name, existing.ID, name),
+		})
+		req.Name = name
+	default:
+		return nil, fmt.Errorf("runner name %s is already used by runner %d, set on_name_conflict to replace or suffix to recover", req.Name, existing.ID)
+	}
+
+	err = client.Post(ctx, fmt.Sprintf("/orgs/%s/actions/runners/generate-jitconfig", client.organization), req, result)
+	if err != nil {
+		return diags, fmt.Errorf("failed to create runner %s after resolving conflict with runner %d: %v", req.Name, existing.ID, err)
+	}
+	return diags, nil
+}
+
+// findRunnerByName returns the organization runner with the given name, or nil if there is none
+func findRunnerByName(ctx context.Context, client *Client, name string) (*SelfHostedRunner, error) {
+	runners, err := listSelfHostedRunners(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	for _, runner := range runners {
+		if runner.Name == name {
+			return &runner, nil
+		}
+	}
+	return nil, nil
+}
+
+// runnerNameSuffixPattern matches the random suffix generateRunnerName appends
+var runnerNameSuffixPattern = regexp.MustCompile(`^-[0-9a-f]{8}$`)
+
+// suppressRunnerNameSuffixDiff ignores the suffix added to the name when on_name_conflict is suffix
+func suppressRunnerNameSuffixDiff(k, old, new string, d *schema.ResourceData) bool {
+	if d.Get("on_name_conflict").(string) != "suffix" || !strings.HasPrefix(old, new) {
+		return false
+	}
+	return runnerNameSuffixPattern.MatchString(strings.TrimPrefix(old, new))
+}
+
+// resourceSelfHostedRunnerCustomizeDiff replaces the runner once its JIT configuration
+// has been used or is older than max_age
+func resourceSelfHostedRunnerCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && d.Get("jit_config_consumed").(bool) {
+		if err := d.SetNewComputed("created_at"); err != nil {
+			return err
+		}
+		return d.ForceNew("created_at")
+	}
+
+	maxAge := d.Get("max_age").(string)
+	createdAt := d.Get("created_at").(string)
+	if d.Id() == "" || maxAge == "" || createdAt == "" {
+		return nil
+	}
+
+	age, err := time.ParseDuration(maxAge)
+	if err != nil {
+		return fmt.Errorf("max_age: %v", err)
+	}
+	created, err := time.Parse(time.RFC3339, createdAt)
+	if err != nil {
+		return fmt.Errorf("created_at: %v", err)
+	}
+
+	if time.Now().After(created.Add(age)) {
+		if err := d.SetNewComputed("created_at"); err != nil {
+			return err
+		}
+		return d.ForceNew("created_at")
+	}
+
+	return nil
+}
+
+// resourceSelfHostedRunnerLabelsCustomizeDiff checks the labels at plan time, so invalid labels
+// do not leave behind a runner whose label update failed
+func resourceSelfHostedRunnerLabelsCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("readonly_labels") || !d.NewValueKnown("labels") {
+		return nil
+	}
+	return checkRunnerLabels(
+		expandStringList(d.Get("readonly_labels").([]interface{})),
+		expandStringList(d.Get("labels").([]interface{})),
+	)
+}
+
+// waitForSelfHostedRunnerOnline polls the runner until its status is online,
+// reporting the last status seen when it gives up
+func waitForSelfHostedRunnerOnline(ctx context.Context, client *Client, runnerID string, timeout time.Duration) error {
+	lastStatus := "unknown"
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"offline"},
+		Target:  []string{"online"},
+		Refresh: func() (interface{}, string, error) {
+			var runner SelfHostedRunner
+			err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s", client.organization, runnerID), &runner)
+			if err != nil {
+				return nil, "", err
+			}
+			lastStatus = runner.Status
+			return &runner, runner.Status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 5 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		return fmt.Errorf("runner %s did not come online, last status: %s: %v", runnerID, lastStatus, err)
+	}
+	return nil
+}
+
+// waitForSelfHostedRunnerOffline polls the runner until it goes offline or disappears, so it can
+// be deleted without interrupting a job. It reports whether the runner disappeared while
+// waiting, with a warning for every status change and one for the total time waited.
+func waitForSelfHostedRunnerOffline(ctx context.Context, client *Client, runnerID string, timeout time.Duration) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	start := time.Now()
+	runnerName := runnerID
+	lastStatus := "unknown"
+	stateConf := &retry.StateChangeConf{
+		Pending: []string{"busy", "idle", "online"},
+		Target:  []string{"offline", "deleted"},
+		Refresh: func() (interface{}, string, error) {
+			var runner SelfHostedRunner
+			var status string
+			err := client.Get(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%s", client.organization, runnerID), &runner)
+			switch {
+			case isNotFound(err):
+				status = "deleted"
+			case err != nil:
+				return nil, "", err
+			case runner.Busy:
+				status = "busy"
+			case runner.Status == "online":
+				status = "idle"
+			default:
+				status = runner.Status
+			}
+			if runner.Name != "" {
+				runnerName = runner.Name
+			}
+
+			if status != lastStatus && status != "offline" && status != "deleted" {
+				diags = append(diags, diag.Diagnostic{
+					Severity: diag.Warning,
+					Summary:  "Waiting for runner to go offline",
+					Detail:   fmt.Sprintf("Runner %s (%s) is %s after %s, so it is deleted once it has gone offline.", runnerName, runnerID, status, time.Since(start).Round(time.Second)),
+				})
+			}
+			lastStatus = status
+			tflog.Info(ctx, "Waiting for runner to go offline", map[string]interface{}{
+				"runner_id":   runnerID,
+				"runner_name": runnerName,
+				"status":      status,
+				"waited":      time.Since(start).Round(time.Second).String(),
+			})
+			return &runner, status, nil
+		},
+		Timeout:    timeout,
+		MinTimeout: 10 * time.Second,
+	}
+
+	if _, err := stateConf.WaitForStateContext(ctx); err != nil {
+		if lastStatus == "busy" || lastStatus == "idle" || lastStatus == "online" {
+			return false, append(diags, diag.Errorf("runner %s (%s) was still %s after waiting %s for it to go offline; raise the delete timeout or set deletion_policy to force", runnerName, runnerID, lastStatus, time.Since(start).Round(time.Second))...)
+		}
+		return false, append(diags, diag.Errorf("failed waiting for runner %s to go offline after %s, last status: %s: %v", runnerID, time.Since(start).Round(time.Second), lastStatus, err)...)
+	}
+
+	diags = append(diags, diag.Diagnostic{
+		Severity: diag.Warning,
+		Summary:  "Runner went offline",
+		Detail:   fmt.Sprintf("Runner %s (%s) was %s after waiting %s.", runnerName, runnerID, lastStatus, time.Since(start).Round(time.Second)),
+	})
+	return lastStatus == "deleted", diags
+}
+
+// decodeJITRunnerSettings extracts the .runner settings from an encoded JIT configuration.
+// The configuration is base64 encoded JSON mapping runner file names to their base64 encoded
+// contents; only .runner is decoded, the credential files are left untouched.
+func decodeJITRunnerSettings(encoded string) (*JITRunnerSettings, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JIT configuration encoding: %v", err)
+	}
+
+	var files map[string]string
+	if err := json.Unmarshal(raw, &files); err != nil {
+		return nil, fmt.Errorf("invalid JIT configuration: %v", err)
+	}
+
+	runnerFile, ok := files[".runner"]
+	if !ok {
+		return nil, fmt.Errorf("JIT configuration has no .runner file")
+	}
+	runnerJSON, err := base64.StdEncoding.DecodeString(runnerFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid .runner encoding: %v", err)
+	}
+
+	// The runner writes its settings files with a UTF-8 byte order mark
+	var settings JITRunnerSettings
+	if err := json.Unmarshal(bytes.TrimPrefix(runnerJSON, []byte("\xef\xbb\xbf")), &settings); err != nil {
+		return nil, fmt.Errorf("invalid .runner file: %v", err)
+	}
+
+	return &settings, nil
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// orderLabels returns current in the order of prior, keeping the spelling from prior for
+// labels that only differ in case, followed by labels that are not in prior. This avoids
+// diffs when the API returns labels in a different order than they were configured.
+func orderLabels(prior, current []string) []string {
+	ordered := make([]string, 0, len(current))
+	used := make([]bool, len(current))
+	for _, p := range prior {
+		for i, c := range current {
+			if !used[i] && strings.EqualFold(p, c) {
+				ordered = append(ordered, p)
+				used[i] = true
+				break
+			}
+		}
+	}
+	for i, c := range current {
+		if !used[i] {
+			ordered = append(ordered, c)
+		}
+	}
+	return ordered
+}
+
+// validateDuration checks that a string is a positive Go duration such as `720h`
+func validateDuration(v interface{}, k string) ([]string, []error) {
+	duration, err := time.ParseDuration(v.(string))
+	if err != nil {
+		return nil, []error{fmt.Errorf("%q must be a duration such as 720h: %v", k, err)}
+	}
+	if duration <= 0 {
+		return nil, []error{fmt.Errorf("%q must be positive", k)}
+	}
+	return nil, nil
+}
+
+// lookupSelfHostedRunnerIDs returns the IDs of the organization runners with the given name
+func lookupSelfHostedRunnerIDs(ctx context.Context, client *Client, name string) ([]string, error) {
+	runners, err := listSelfHostedRunners(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, 1)
+	for _, runner := range runners {
+		if runner.Name == name {
+			ids = append(ids, strconv.Itoa(runner.ID))
+		}
+	}
+	return ids, nil
+}
+
+// listRunners lists the runners of a runner group, or of the organization when no group is given
+func listRunners(ctx context.Context, client *Client, runnerGroupID int) ([]SelfHostedRunner, error) {
+	if runnerGroupID > 0 {
+		return listRunnerGroupRunners(ctx, client, strconv.Itoa(runnerGroupID))
+	}
+	return listSelfHostedRunners(ctx, client)
+}
+
+// listSelfHostedRunners returns every self-hosted runner in the organization
+func listSelfHostedRunners(ctx context.Context, client *Client) ([]SelfHostedRunner, error) {
+	return getAllPages(ctx, client, fmt.Sprintf("/orgs/%s/actions/runners", client.organization), func(l *SelfHostedRunnerList) (int, []SelfHostedRunner) {
+		return l.TotalCount, l.Runners
+	})
+}
+
 func dataSourceRunnerApplications() *schema.Resource {
 	return &schema.Resource{
 		Description: "Retrieves available runner applications for download.",
@@ -422,6 +1164,11 @@ func dataSourceRunnerApplications() *schema.Resource {
 							Computed:    true,
 							Description: "Filename of the runner application",
 						},
+						"sha256_checksum": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "SHA-256 checksum of the runner application package",
+						},
 					},
 				},
 			},
@@ -481,10 +1228,11 @@ func dataSourceRunnerApplicationsRead(ctx context.Context, d *schema.ResourceDat
 	applicationList := make([]map[string]interface{}, len(applications))
 	for i, app := range applications {
 		applicationList[i] = map[string]interface{}{
-			"os":           app.OS,
-			"architecture": app.Architecture,
-			"download_url": app.DownloadURL,
-			"filename":     app.Filename,
+			"os":              app.OS,
+			"architecture":    app.Architecture,
+			"download_url":    app.DownloadURL,
+			"filename":        app.Filename,
+			"sha256_checksum": app.SHA256Checksum,
 		}
 	}
 
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+// poolMember is a runner created by a self_hosted_runner_pool
+type poolMember struct {
+	ID               int
+	Name             string
+	EncodedJITConfig string
+}
+
+func resourceSelfHostedRunnerPool() *schema.Resource {
+	return &schema.Resource{
+		Description:   "Manages a pool of GitHub self-hosted runners, each with its own JIT configuration.",
+		CreateContext: resourceSelfHostedRunnerPoolCreate,
+		ReadContext:   resourceSelfHostedRunnerPoolRead,
+		UpdateContext: resourceSelfHostedRunnerPoolUpdate,
+		DeleteContext: resourceSelfHostedRunnerPoolDelete,
+		CustomizeDiff: resourceSelfHostedRunnerPoolCustomizeDiff,
+		Schema: map[string]*schema.Schema{
+			"name_prefix": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringLenBetween(1, 54),
+				Description:  "Prefix of the runner names. Each runner is named `<name_prefix>-<random suffix>`",
+			},
+			"size": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Number of runners in the pool. Changing it adds or removes runners without recreating the others",
+			},
+			"runner_group_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				ForceNew:    true,
+				Description: "ID of the runner group to add the runners to",
+			},
+			"labels": {
+				Type:        schema.TypeList,
+				Required:    true,
+				ForceNew:    true,
+				MinItems:    1,
+				Elem:        &schema.Schema{Type: schema.TypeString, ValidateFunc: validateRunnerLabel},
+				Description: "Labels of every runner in the pool. They are set as read-only labels in each runner's JIT configuration, so changing them replaces the pool",
+			},
+			"work_folder": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ForceNew:    true,
+				Default:     "_work",
+				Description: "Working directory for job execution",
+			},
+			"parallelism": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      10,
+				ValidateFunc: validation.IntBetween(1, 50),
+				Description:  "Maximum number of runners created or removed at the same time",
+			},
+			"runners": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "Runners in the pool",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"encoded_jit_config": {
+							Type:      schema.TypeString,
+							Computed:  true,
+							Sensitive: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourceSelfHostedRunnerPoolCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	d.SetId(d.Get("name_prefix").(string))
+
+	members, err := createPoolMembers(ctx, client, d, d.Get("size").(int))
+	// Keep the runners that were created so they are not orphaned
+	d.Set("runners", flattenPoolMembers(members))
+	if err != nil {
+		return diag.Errorf("failed to create runner pool %s: %v", d.Id(), err)
+	}
+
+	return resourceSelfHostedRunnerPoolRead(ctx, d, m)
+}
+
+func resourceSelfHostedRunnerPoolRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runners, err := listRunners(ctx, client, d.Get("runner_group_id").(int))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	existing := make(map[int]bool, len(runners))
+	for _, runner := range runners {
+		existing[runner.ID] = true
+	}
+
+	// Drop members that no longer exist, e.g. ephemeral runners that finished their job,
+	// so the next plan replaces them
+	members := make([]poolMember, 0)
+	for _, member := range expandPoolMembers(d.Get("runners").([]interface{})) {
+		if !existing[member.ID] {
+			tflog.Warn(ctx, "Pool runner no longer exists and will be replaced", map[string]interface{}{
+				"runner_id":   member.ID,
+				"runner_name": member.Name,
+			})
+			continue
+		}
+		members = append(members, member)
+	}
+	d.Set("runners", flattenPoolMembers(members))
+
+	return nil
+}
+
+func resourceSelfHostedRunnerPoolUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	size := d.Get("size").(int)
+	oldRunners, _ := d.GetChange("runners")
+	members := expandPoolMembers(oldRunners.([]interface{}))
+	parallelism := d.Get("parallelism").(int)
+
+	if len(members) > size {
+		remaining, err := removePoolMembers(ctx, client, members, len(members)-size, parallelism)
+		d.Set("runners", flattenPoolMembers(remaining))
+		if err != nil {
+			return diag.Errorf("failed to shrink runner pool %s: %v", d.Id(), err)
+		}
+		members = remaining
+	}
+
+	if len(members) < size {
+		added, err := createPoolMembers(ctx, client, d, size-len(members))
+		members = append(members, added...)
+		d.Set("runners", flattenPoolMembers(members))
+		if err != nil {
+			return diag.Errorf("failed to grow runner pool %s: %v", d.Id(), err)
+		}
+	}
+
+	return resourceSelfHostedRunnerPoolRead(ctx, d, m)
+}
+
+func resourceSelfHostedRunnerPoolDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	members := expandPoolMembers(d.Get("runners").([]interface{}))
+	remaining, err := removePoolMembers(ctx, client, members, len(members), d.Get("parallelism").(int))
+	if err != nil {
+		d.Set("runners", flattenPoolMembers(remaining))
+		return diag.Errorf("failed to delete runner pool %s: %v", d.Id(), err)
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// resourceSelfHostedRunnerPoolCustomizeDiff plans a change to runners whenever the pool
+// has to grow or shrink, including when members disappeared outside Terraform
+func resourceSelfHostedRunnerPoolCustomizeDiff(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Pool labels are set through the JIT configuration, so default labels are allowed
+	if d.NewValueKnown("labels") {
+		if err := checkRunnerLabels(expandStringList(d.Get("labels").([]interface{})), nil); err != nil {
+			return err
+		}
+	}
+
+	if d.Id() == "" || !d.NewValueKnown("size") {
+		return nil
+	}
+	if len(d.Get("runners").([]interface{})) != d.Get("size").(int) {
+		return d.SetNewComputed("runners")
+	}
+	return nil
+}
+
+// createPoolMembers generates count new runners concurrently. It returns the runners that
+// were created even when some of them failed.
+func createPoolMembers(ctx context.Context, client *Client, d *schema.ResourceData, count int) ([]poolMember, error) {
+	namePrefix := d.Get("name_prefix").(string)
+	runnerGroupID := d.Get("runner_group_id").(int)
+	labels := expandStringList(d.Get("labels").([]interface{}))
+	workFolder := d.Get("work_folder").(string)
+
+	created := make([]*poolMember, count)
+	errs := runConcurrently(count, d.Get("parallelism").(int), func(i int) error {
+		name, err := generateRunnerName(namePrefix)
+		if err != nil {
+			return err
+		}
+
+		req := &JITConfigRequest{
+			Name:           name,
+			RunnerGroupID:  runnerGroupID,
+			ReadOnlyLabels: labels,
+			WorkFolder:     workFolder,
+		}
+
+		var result JITConfigResponse
+		err = client.Post(ctx, fmt.Sprintf("/orgs/%s/actions/runners/generate-jitconfig", client.organization), req, &result)
+		if err != nil {
+			return fmt.Errorf("failed to create runner %s: %v", name, err)
+		}
+
+		created[i] = &poolMember{
+			ID:               result.Runner.ID,
+			Name:             result.Runner.Name,
+			EncodedJITConfig: result.EncodedJITConfig,
+		}
+		return nil
+	})
+
+	members := make([]poolMember, 0, count)
+	for _, member := range created {
+		if member != nil {
+			members = append(members, *member)
+		}
+	}
+
+	return members, errors.Join(errs...)
+}
+
+// removePoolMembers deletes count runners from the pool, preferring offline runners and
+// never removing a busy one. It returns the members that remain.
+func removePoolMembers(ctx context.Context, client *Client, members []poolMember, count, parallelism int) ([]poolMember, error) {
+	runners, err := listSelfHostedRunners(ctx, client)
+	if err != nil {
+		return members, err
+	}
+	status := make(map[int]SelfHostedRunner, len(runners))
+	for _, runner := range runners {
+		status[runner.ID] = runner
+	}
+
+	// Newest members go first, offline before idle
+	var candidates []int
+	for _, wantOffline := range []bool{true, false} {
+		for i := len(members) - 1; i >= 0 && len(candidates) < count; i-- {
+			runner, ok := status[members[i].ID]
+			if ok && runner.Busy {
+				continue
+			}
+			if (!ok || runner.Status == "offline") == wantOffline {
+				candidates = append(candidates, i)
+			}
+		}
+	}
+
+	removed := make([]bool, len(members))
+	errs := runConcurrently(len(candidates), parallelism, func(i int) error {
+		member := members[candidates[i]]
+		err := client.Delete(ctx, fmt.Sprintf("/orgs/%s/actions/runners/%d", client.organization, member.ID), nil)
+		if err != nil && !isNotFound(err) {
+			return fmt.Errorf("failed to delete runner %s: %v", member.Name, err)
+		}
+		removed[candidates[i]] = true
+		return nil
+	})
+	if len(candidates) < count {
+		errs = append(errs, fmt.Errorf("%d runners are busy and were not removed", count-len(candidates)))
+	}
+
+	remaining := make([]poolMember, 0, len(members))
+	for i, member := range members {
+		if !removed[i] {
+			remaining = append(remaining, member)
+		}
+	}
+
+	return remaining, errors.Join(errs...)
+}
+
+// runConcurrently calls fn for every index in [0, n) with at most parallelism calls in flight
+func runConcurrently(n, parallelism int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+// generateRunnerName returns prefix followed by a random suffix
+func generateRunnerName(prefix string) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate runner name: %v", err)
+	}
+	return fmt.Sprintf("%s-%s", prefix, hex.EncodeToString(suffix)), nil
+}
+
+func expandPoolMembers(configured []interface{}) []poolMember {
+	members := make([]poolMember, 0, len(configured))
+	for _, v := range configured {
+		member := v.(map[string]interface{})
+		members = append(members, poolMember{
+			ID:               member["id"].(int),
+			Name:             member["name"].(string),
+			EncodedJITConfig: member["encoded_jit_config"].(string),
+		})
+	}
+	return members
+}
+
+func flattenPoolMembers(members []poolMember) []map[string]interface{} {
+	runners := make([]map[string]interface{}, len(members))
+	for i, member := range members {
+		runners[i] = map[string]interface{}{
+			"id":                 member.ID,
+			"name":               member.Name,
+			"encoded_jit_config": member.EncodedJITConfig,
+		}
+	}
+	return runners
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+// newSelfHostedRunnerTestServer serves runner 1 in runner group 1. A nil runner is reported as
+// not found.
+func newSelfHostedRunnerTestServer(t *testing.T, runner *SelfHostedRunner) *Client {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/orgs/o/actions/runners/1", func(w http.ResponseWriter, r *http.Request) {
+		if runner == nil {
+			http.Error(w, `{"message":"Not Found"}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(runner)
+	})
+	mux.HandleFunc("/orgs/o/actions/runner-groups/1/runners", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(SelfHostedRunnerList{TotalCount: 1, Runners: []SelfHostedRunner{{ID: 1, Name: "runner"}}})
+	})
+	mux.HandleFunc("/orgs/o/actions/runner-groups/1", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(RunnerGroup{ID: 1, Name: "Default", Default: true})
+	})
+
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return &Client{httpClient: srv.Client(), baseURL: srv.URL, organization: "o"}
+}
+
+// selfHostedRunnerTestState is the state of runner 1 before its JIT configuration was used
+func selfHostedRunnerTestState(seenOnline bool) *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: "1",
+		Attributes: map[string]string{
+			"id":                     "1",
+			"name":                   "runner",
+			"runner_group_id":        "1",
+			"runner_group_name":      "Default",
+			"on_name_conflict":       "fail",
+			"readonly_labels.#":      "1",
+			"readonly_labels.0":      "self-hosted",
+			"labels.#":               "0",
+			"jit_config.#":           "0",
+			"work_folder":            "_work",
+			"wait_for_online":        "false",
+			"authoritative":          "true",
+			"created_at":             time.Now().UTC().Format(time.RFC3339),
+			"jit_config_consumed":    "false",
+			"jit_config_seen_online": boolString(seenOnline),
+			"deletion_policy":        "fail",
+		},
+	}
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func selfHostedRunnerTestRunner(status string, busy bool) *SelfHostedRunner {
+	return &SelfHostedRunner{
+		ID:        1,
+		Name:      "runner",
+		OS:        "Linux",
+		Status:    status,
+		Busy:      busy,
+		Ephemeral: true,
+		Labels:    []RunnerLabel{{Name: "self-hosted", Type: "read-only"}},
+	}
+}
+
+func TestSelfHostedRunnerReadJITConfigConsumed(t *testing.T) {
+	cases := []struct {
+		name         string
+		seenOnline   bool
+		runner       *SelfHostedRunner
+		wantSeen     bool
+		wantConsumed bool
+	}{
+		{name: "offline before first start", runner: selfHostedRunnerTestRunner("offline", false)},
+		{name: "online and idle", runner: selfHostedRunnerTestRunner("online", false), wantSeen: true},
+		{name: "online and idle on a later refresh", seenOnline: true, runner: selfHostedRunnerTestRunner("online", false), wantSeen: true},
+		{name: "busy", runner: selfHostedRunnerTestRunner("online", true), wantSeen: true, wantConsumed: true},
+		{name: "offline after online", seenOnline: true, runner: selfHostedRunnerTestRunner("offline", false), wantSeen: true, wantConsumed: true},
+	}
+
+	r := resourceSelfHostedRunner()
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":            "runner",
+		"readonly_labels": []interface{}{"self-hosted"},
+	})
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			client := newSelfHostedRunnerTestServer(t, tc.runner)
+
+			d := r.Data(selfHostedRunnerTestState(tc.seenOnline))
+			diags := r.ReadContext(ctx, d, client)
+			if diags.HasError() {
+				t.Fatalf("unexpected error: %v", diags)
+			}
+			if got := d.Get("jit_config_seen_online").(bool); got != tc.wantSeen {
+				t.Errorf("jit_config_seen_online = %t, want %t", got, tc.wantSeen)
+			}
+			if got := d.Get("jit_config_consumed").(bool); got != tc.wantConsumed {
+				t.Errorf("jit_config_consumed = %t, want %t", got, tc.wantConsumed)
+			}
+			if got := len(diags) > 0; got != tc.wantConsumed {
+				t.Errorf("warning reported = %t, want %t: %v", got, tc.wantConsumed, diags)
+			}
+
+			diff, err := r.Diff(ctx, d.State(), config, client)
+			if err != nil {
+				t.Fatalf("unexpected diff error: %v", err)
+			}
+			if tc.wantConsumed {
+				if diff == nil || !diff.RequiresNew() {
+					t.Errorf("expected the plan to replace the runner, got %v", diff)
+				}
+			} else if diff != nil && !diff.Empty() {
+				t.Errorf("expected no changes, got %v", diff)
+			}
+		})
+	}
+}
+
+func TestSelfHostedRunnerReadNotFound(t *testing.T) {
+	r := resourceSelfHostedRunner()
+	client := newSelfHostedRunnerTestServer(t, nil)
+
+	d := r.Data(selfHostedRunnerTestState(true))
+	diags := r.ReadContext(context.Background(), d, client)
+	if diags.HasError() {
+		t.Fatalf("unexpected error: %v", diags)
+	}
+	if d.Id() != "" {
+		t.Errorf("expected the runner to be removed from state, got ID %q", d.Id())
+	}
+	if len(diags) != 1 {
+		t.Errorf("expected one warning, got %v", diags)
+	}
+}
+
+func TestSelfHostedRunnerWaitForOnlineNoDiff(t *testing.T) {
+	ctx := context.Background()
+	r := resourceSelfHostedRunner()
+	client := newSelfHostedRunnerTestServer(t, selfHostedRunnerTestRunner("online", false))
+
+	state := selfHostedRunnerTestState(false)
+	state.Attributes["wait_for_online"] = "true"
+	d := r.Data(state)
+	if err := waitForSelfHostedRunnerOnline(ctx, client, d.Id(), time.Minute); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if diags := r.ReadContext(ctx, d, client); len(diags) > 0 {
+		t.Fatalf("unexpected diagnostics: %v", diags)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{
+		"name":            "runner",
+		"readonly_labels": []interface{}{"self-hosted"},
+		"wait_for_online": true,
+	})
+	diff, err := r.Diff(ctx, d.State(), config, client)
+	if err != nil {
+		t.Fatalf("unexpected diff error: %v", err)
+	}
+	if diff != nil && !diff.Empty() {
+		t.Errorf("expected no changes for a runner that has just come online, got %v", diff)
+	}
+}
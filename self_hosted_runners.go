@@ -0,0 +1,210 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func dataSourceSelfHostedRunners() *schema.Resource {
+	return &schema.Resource{
+		Description: "Retrieves all GitHub self-hosted runners in the organization or a runner group, optionally filtered.",
+		ReadContext: dataSourceSelfHostedRunnersRead,
+		Schema: map[string]*schema.Schema{
+			"runner_group_id": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Description: "Only return runners in this runner group",
+			},
+			"status": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice([]string{"online", "offline"}, false),
+				Description:  "Only return runners with this status: `online` or `offline`",
+			},
+			"busy": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return runners whose `busy` flag matches this value",
+			},
+			"ephemeral": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Description: "Only return runners whose `ephemeral` flag matches this value",
+			},
+			"os": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return runners with this operating system, e.g. `Linux`. Case-insensitive",
+			},
+			"labels_any": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only return runners with at least one of these labels",
+			},
+			"labels_all": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "Only return runners with all of these labels",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Regular expression the runner name must match",
+			},
+			"runners": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "List of matching runners",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"os": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"status": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"busy": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"ephemeral": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"labels": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeInt,
+										Computed: true,
+									},
+									"name": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"type": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceSelfHostedRunnersRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	client := m.(*Client)
+
+	runnerGroupID := d.Get("runner_group_id").(int)
+	runners, err := listRunners(ctx, client, runnerGroupID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	config := d.GetRawConfig()
+	status := d.Get("status").(string)
+	osName := d.Get("os").(string)
+	labelsAny := expandStringList(d.Get("labels_any").(*schema.Set).List())
+	labelsAll := expandStringList(d.Get("labels_all").(*schema.Set).List())
+
+	result := make([]map[string]interface{}, 0, len(runners))
+	for _, runner := range runners {
+		if nameRegex != nil && !nameRegex.MatchString(runner.Name) {
+			continue
+		}
+		if status != "" && runner.Status != status {
+			continue
+		}
+		if !config.GetAttr("busy").IsNull() && runner.Busy != d.Get("busy").(bool) {
+			continue
+		}
+		if !config.GetAttr("ephemeral").IsNull() && runner.Ephemeral != d.Get("ephemeral").(bool) {
+			continue
+		}
+		if osName != "" && !strings.EqualFold(runner.OS, osName) {
+			continue
+		}
+		if len(labelsAny) > 0 && !runnerHasAnyLabel(runner, labelsAny) {
+			continue
+		}
+		if !runnerHasAllLabels(runner, labelsAll) {
+			continue
+		}
+
+		labels := make([]map[string]interface{}, len(runner.Labels))
+		for i, label := range runner.Labels {
+			labels[i] = map[string]interface{}{
+				"id":   label.ID,
+				"name": label.Name,
+				"type": label.Type,
+			}
+		}
+
+		result = append(result, map[string]interface{}{
+			"id":        runner.ID,
+			"name":      runner.Name,
+			"os":        runner.OS,
+			"status":    runner.Status,
+			"busy":      runner.Busy,
+			"ephemeral": runner.Ephemeral,
+			"labels":    labels,
+		})
+	}
+
+	if runnerGroupID > 0 {
+		d.SetId("self-hosted-runners-" + strconv.Itoa(runnerGroupID))
+	} else {
+		d.SetId("self-hosted-runners")
+	}
+	d.Set("runners", result)
+
+	return nil
+}
+
+func runnerHasAnyLabel(runner SelfHostedRunner, labels []string) bool {
+	for _, label := range labels {
+		if hasRunnerLabel(runner.Labels, label) {
+			return true
+		}
+	}
+	return false
+}
+
+func runnerHasAllLabels(runner SelfHostedRunner, labels []string) bool {
+	for _, label := range labels {
+		if !hasRunnerLabel(runner.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
@@ -54,25 +54,22 @@ type RunnerGroupList struct {
 
 // SelfHostedRunner represents a GitHub self-hosted runner
 type SelfHostedRunner struct {
-	ID        int    `json:"id,omitempty"`
-	Name      string `json:"name"`
-	OS        string `json:"os,omitempty"`
-	Status    string `json:"status,omitempty"`
-	Busy      bool   `json:"busy,omitempty"`
-	Ephemeral bool   `json:"ephemeral,omitempty"`
-	Labels    []struct {
-		ID   int    `json:"id,omitempty"`
-		Name string `json:"name"`
-		Type string `json:"type,omitempty"`
-	} `json:"labels,omitempty"`
+	ID        int           `json:"id,omitempty"`
+	Name      string        `json:"name"`
+	OS        string        `json:"os,omitempty"`
+	Status    string        `json:"status,omitempty"`
+	Busy      bool          `json:"busy,omitempty"`
+	Ephemeral bool          `json:"ephemeral,omitempty"`
+	Labels    []RunnerLabel `json:"labels,omitempty"`
 }
 
 // RunnerApplication represents a GitHub runner application download
 type RunnerApplication struct {
-	OS           string `json:"os,omitempty"`
-	Architecture string `json:"architecture,omitempty"`
-	DownloadURL  string `json:"download_url,omitempty"`
-	Filename     string `json:"filename,omitempty"`
+	OS             string `json:"os,omitempty"`
+	Architecture   string `json:"architecture,omitempty"`
+	DownloadURL    string `json:"download_url,omitempty"`
+	Filename       string `json:"filename,omitempty"`
+	SHA256Checksum string `json:"sha256_checksum,omitempty"`
 }
 
 // JITConfigRequest represents the request to create a JIT configuration
@@ -89,6 +86,17 @@ type JITConfigResponse struct {
 	EncodedJITConfig string           `json:"encoded_jit_config"`
 }
 
+// JITRunnerSettings represents the .runner file inside an encoded JIT configuration
+type JITRunnerSettings struct {
+	AgentID    int    `json:"AgentId"`
+	AgentName  string `json:"AgentName"`
+	PoolID     int    `json:"PoolId"`
+	PoolName   string `json:"PoolName"`
+	ServerURL  string `json:"ServerUrl"`
+	GitHubURL  string `json:"GitHubUrl"`
+	WorkFolder string `json:"WorkFolder"`
+}
+
 // RegistrationToken represents a GitHub registration token
 type RegistrationToken struct {
 	Token     string `json:"token,omitempty"`
@@ -142,7 +150,7 @@ type UpdateRunnerGroupRequest struct {
 	Visibility               string   `json:"visibility,omitempty"`
 	AllowsPublicRepositories *bool    `json:"allows_public_repositories,omitempty"`
 	RestrictedToWorkflows    *bool    `json:"restricted_to_workflows,omitempty"`
-	SelectedWorkflows        []string `json:"selected_workflows,omitempty"`
+	SelectedWorkflows        []string `json:"selected_workflows"`
 	NetworkConfigurationID   *string  `json:"network_configuration_id,omitempty"`
 }
 
@@ -162,16 +170,58 @@ type SelfHostedRunnerList struct {
 	Runners    []SelfHostedRunner `json:"runners"`
 }
 
+// Repository represents a GitHub repository
+type Repository struct {
+	ID         int      `json:"id"`
+	Name       string   `json:"name"`
+	FullName   string   `json:"full_name"`
+	Private    bool     `json:"private"`
+	Visibility string   `json:"visibility,omitempty"`
+	Archived   bool     `json:"archived,omitempty"`
+	Topics     []string `json:"topics,omitempty"`
+}
+
+// RepositoryList represents the response for listing repositories with access to a runner group
+type RepositoryList struct {
+	TotalCount   int          `json:"total_count"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// RepositoryCustomPropertyValues represents the custom property values set on a repository
+type RepositoryCustomPropertyValues struct {
+	RepositoryID       int                   `json:"repository_id"`
+	RepositoryName     string                `json:"repository_name"`
+	RepositoryFullName string                `json:"repository_full_name"`
+	Properties         []CustomPropertyValue `json:"properties"`
+}
+
+// CustomPropertyValue represents a custom property value, which is a list for multi-select properties
+type CustomPropertyValue struct {
+	PropertyName string      `json:"property_name"`
+	Value        interface{} `json:"value"`
+}
+
+// RepositoryContent represents a file returned by the repository contents API
+type RepositoryContent struct {
+	Type string `json:"type"`
+	Name string `json:"name"`
+	Path string `json:"path"`
+	SHA  string `json:"sha"`
+}
+
 // HostedRunner represents a GitHub-hosted runner
 type HostedRunner struct {
 	ID            int    `json:"id,omitempty"`
 	Name          string `json:"name"`
 	RunnerGroupID int    `json:"runner_group_id,omitempty"`
 	Platform      string `json:"platform,omitempty"`
-	Image         struct {
-		ID   string `json:"id,omitempty"`
-		Size int    `json:"size,omitempty"`
-	} `json:"image,omitempty"`
+	ImageDetails  struct {
+		ID          string `json:"id,omitempty"`
+		SizeGB      int    `json:"size_gb,omitempty"`
+		DisplayName string `json:"display_name,omitempty"`
+		Source      string `json:"source,omitempty"`
+		Version     string `json:"version,omitempty"`
+	} `json:"image_details,omitempty"`
 	MachineSizeDetails struct {
 		ID        string `json:"id,omitempty"`
 		CPUCores  int    `json:"cpu_cores,omitempty"`
@@ -194,3 +244,105 @@ type HostedRunnerList struct {
 	TotalCount int            `json:"total_count"`
 	Runners    []HostedRunner `json:"runners"`
 }
+
+// HostedRunnerImage identifies the image to create a GitHub-hosted runner from
+type HostedRunnerImage struct {
+	ID      string `json:"id"`
+	Source  string `json:"source,omitempty"`
+	Version string `json:"version,omitempty"`
+}
+
+// CreateHostedRunnerRequest represents the request to create a GitHub-hosted runner
+type CreateHostedRunnerRequest struct {
+	Name           string            `json:"name"`
+	Image          HostedRunnerImage `json:"image"`
+	Size           string            `json:"size"`
+	RunnerGroupID  int               `json:"runner_group_id"`
+	MaximumRunners int               `json:"maximum_runners,omitempty"`
+	EnableStaticIP bool              `json:"enable_static_ip,omitempty"`
+}
+
+// UpdateHostedRunnerRequest represents the request to update a GitHub-hosted runner
+type UpdateHostedRunnerRequest struct {
+	Name           string `json:"name,omitempty"`
+	RunnerGroupID  int    `json:"runner_group_id,omitempty"`
+	MaximumRunners int    `json:"maximum_runners,omitempty"`
+	EnableStaticIP *bool  `json:"enable_static_ip,omitempty"`
+	ImageVersion   string `json:"image_version,omitempty"`
+}
+
+// HostedRunnerImageDetails represents an image available to GitHub-hosted runners
+type HostedRunnerImageDetails struct {
+	ID          string `json:"id"`
+	Platform    string `json:"platform"`
+	SizeGB      int    `json:"size_gb"`
+	DisplayName string `json:"display_name"`
+	Source      string `json:"source"`
+}
+
+// HostedRunnerImageList represents the response for listing GitHub-owned or partner images
+type HostedRunnerImageList struct {
+	TotalCount int                        `json:"total_count"`
+	Images     []HostedRunnerImageDetails `json:"images"`
+}
+
+// HostedRunnerMachineSpec represents a machine size available to GitHub-hosted runners
+type HostedRunnerMachineSpec struct {
+	ID        string `json:"id"`
+	CPUCores  int    `json:"cpu_cores"`
+	MemoryGB  int    `json:"memory_gb"`
+	StorageGB int    `json:"storage_gb"`
+}
+
+// HostedRunnerMachineSpecList represents the response for listing machine sizes
+type HostedRunnerMachineSpecList struct {
+	TotalCount   int                       `json:"total_count"`
+	MachineSpecs []HostedRunnerMachineSpec `json:"machine_specs"`
+}
+
+// HostedRunnerPlatformList represents the response for listing platforms
+type HostedRunnerPlatformList struct {
+	TotalCount int      `json:"total_count"`
+	Platforms  []string `json:"platforms"`
+}
+
+// HostedRunnerLimits represents the GitHub-hosted runner limits of an organization
+type HostedRunnerLimits struct {
+	PublicIPs struct {
+		Maximum      int `json:"maximum"`
+		CurrentUsage int `json:"current_usage"`
+	} `json:"public_ips"`
+}
+
+// HostedRunnerCustomImage represents an organization custom image for GitHub-hosted runners
+type HostedRunnerCustomImage struct {
+	ID                int    `json:"id"`
+	Platform          string `json:"platform"`
+	TotalVersionsSize int    `json:"total_versions_size"`
+	Name              string `json:"name"`
+	Source            string `json:"source"`
+	VersionsCount     int    `json:"versions_count"`
+	LatestVersion     string `json:"latest_version"`
+	State             string `json:"state"`
+}
+
+// HostedRunnerCustomImageList represents the response for listing custom images
+type HostedRunnerCustomImageList struct {
+	TotalCount int                       `json:"total_count"`
+	Images     []HostedRunnerCustomImage `json:"images"`
+}
+
+// HostedRunnerCustomImageVersion represents a version of a custom image
+type HostedRunnerCustomImageVersion struct {
+	Version      string `json:"version"`
+	State        string `json:"state"`
+	SizeGB       int    `json:"size_gb"`
+	CreatedOn    string `json:"created_on"`
+	StateDetails string `json:"state_details"`
+}
+
+// HostedRunnerCustomImageVersionList represents the response for listing custom image versions
+type HostedRunnerCustomImageVersionList struct {
+	TotalCount    int                              `json:"total_count"`
+	ImageVersions []HostedRunnerCustomImageVersion `json:"image_versions"`
+}